@@ -90,8 +90,8 @@ func b58Decode(input string) (output []byte, err error) {
 		for f := true; i < len(input) && (f || i%10 != 0); i++ {
 			tmp := b58table[input[i]]
 			if tmp == 255 {
-				msg := "invalid Base58 input string at character \"%c\", position %d"
-				return output, fmt.Errorf(msg, input[i], i)
+				msg := "%w at character \"%c\", position %d"
+				return output, fmt.Errorf(msg, ErrInvalidBase58, input[i], i)
 			}
 			a = a*58 + int64(tmp)
 			if !f {
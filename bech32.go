@@ -0,0 +1,82 @@
+// reference: https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
+package mderive
+
+import (
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// bech32Encode encodes data with the human readable part hrp as bech32, it is
+// used by age for identities and recipients
+func bech32Encode(hrp string, data []byte) string {
+	hrp = strings.ToLower(hrp)
+	values := convertBits(data, 8, 5)
+
+	checksumInput := append(bech32ExpandHRP(hrp), values...)
+	polymod := bech32Polymod(append(checksumInput, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var builder strings.Builder
+	builder.WriteString(hrp)
+	builder.WriteByte('1')
+	for _, value := range values {
+		builder.WriteByte(bech32Charset[value])
+	}
+	for i := 0; i < 6; i++ {
+		builder.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+
+	return builder.String()
+}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, value := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+// convertBits regroups data of fromBits into groups of toBits with zero padding
+func convertBits(data []byte, fromBits, toBits uint) []byte {
+	var output []byte
+	acc, bits := uint32(0), uint(0)
+	maxValue := uint32(1)<<toBits - 1
+
+	for _, value := range data {
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			output = append(output, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if bits > 0 {
+		output = append(output, byte(acc<<(toBits-bits)&maxValue))
+	}
+
+	return output
+}
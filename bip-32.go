@@ -7,6 +7,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
+	"math/big"
 )
 
 const (
@@ -74,6 +75,20 @@ func (key *Key) PublicKey() *Key {
 	}
 }
 
+// Fingerprint returns the first 4 bytes of hash160 of the public key
+func (key *Key) Fingerprint() ([]byte, error) {
+	identifier, err := hash160(key.PublicKey().Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return identifier[:4], nil
+}
+
+// NewChild derives the child key at childIndex. As bip-32 specifies, the child
+// is invalid if IL >= n, or the child private key is zero, or the child public
+// key is the point at infinity, ErrInvalidChildKey is returned so the caller
+// can proceed with the next index, see NextValidChild.
 func (key *Key) NewChild(childIndex uint32) (*Key, error) {
 	if !key.IsPrivate && childIndex >= FirstHardenedChild {
 		return nil, ErrHardnedChildPublicKey
@@ -84,6 +99,38 @@ func (key *Key) NewChild(childIndex uint32) (*Key, error) {
 		return nil, err
 	}
 
+	return key.childFromIntermediary(childIndex, intermediary)
+}
+
+// NextValidChild derives the child at childIndex, or at the next index whose
+// child is valid. The index never crosses from unhardened to hardened range or
+// wraps around, ErrChildIndexExhausted is returned if no index is left.
+func (key *Key) NextValidChild(childIndex uint32) (*Key, uint32, error) {
+	return nextValidChild(childIndex, key.NewChild)
+}
+
+func nextValidChild(childIndex uint32, newChild func(uint32) (*Key, error)) (*Key, uint32, error) {
+	hardened := childIndex >= FirstHardenedChild
+	for {
+		child, err := newChild(childIndex)
+		if err != ErrInvalidChildKey {
+			return child, childIndex, err
+		}
+
+		childIndex++
+		if (childIndex >= FirstHardenedChild) != hardened {
+			return nil, 0, ErrChildIndexExhausted
+		}
+	}
+}
+
+// childFromIntermediary derives the child by I = IL || IR of HMAC-SHA512
+func (key *Key) childFromIntermediary(childIndex uint32, intermediary []byte) (*Key, error) {
+	il := new(big.Int).SetBytes(intermediary[:32])
+	if il.Cmp(secp256k1.Params().N) >= 0 {
+		return nil, ErrInvalidChildKey
+	}
+
 	child := &Key{
 		ChildNumber: uint32Bytes(childIndex),
 		ChainCode:   intermediary[32:],
@@ -99,10 +146,12 @@ func (key *Key) NewChild(childIndex uint32) (*Key, error) {
 		}
 		child.FingerPrint = fingerprint[:4]
 		child.Key = addPrivateKeys(intermediary[:32], key.Key)
-	} else {
-		keyBytes := publicKeyForPrivateKey(intermediary[:32])
 
-		err := validateChildPublicKey(keyBytes)
+		if new(big.Int).SetBytes(child.Key).Sign() == 0 {
+			return nil, ErrInvalidChildKey
+		}
+	} else {
+		childKey, err := addChildPublicKey(il, key.Key)
 		if err != nil {
 			return nil, err
 		}
@@ -113,7 +162,7 @@ func (key *Key) NewChild(childIndex uint32) (*Key, error) {
 			return nil, err
 		}
 		child.FingerPrint = fingerprint[:4]
-		child.Key = addPublicKeys(keyBytes, key.Key)
+		child.Key = childKey
 	}
 
 	return child, nil
@@ -176,40 +225,111 @@ func (key *Key) String() string {
 	return key.Base58Encode()
 }
 
+// Deserialize decodes the 82 bytes extended key. The checksum is verified
+// before any field is read, and a key is returned only if it passes Validate,
+// so a decoded key can be trusted.
 func Deserialize(data []byte) (*Key, error) {
 	if len(data) != 82 {
 		return nil, ErrSerializedKeyWrongSize
 	}
-	var key = &Key{}
-	key.Version = data[0:4]
-	key.Depth = data[4]
-	key.FingerPrint = data[5:9]
-	key.ChildNumber = data[9:13]
-	key.ChainCode = data[13:45]
 
-	if data[45] == byte(0) {
+	cs, err := checksum(data[:78])
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(cs, data[78:]) {
+		return nil, ErrInvalidChecksum
+	}
+
+	// the key doesn't share memory with data
+	payload := bytes.Clone(data[:78])
+	key := &Key{
+		Version:     payload[0:4],
+		Depth:       payload[4],
+		FingerPrint: payload[5:9],
+		ChildNumber: payload[9:13],
+		ChainCode:   payload[13:45],
+	}
+
+	prefix := payload[45]
+	switch {
+	case bytes.Equal(key.Version, PrivateWalletVersion):
+		if prefix == 0x02 || prefix == 0x03 {
+			return nil, ErrKeyVersionMismatch
+		}
+		if prefix != 0x00 {
+			return nil, ErrPrivateKeyPrefix
+		}
 		key.IsPrivate = true
-		key.Key = data[46:78]
-	} else {
-		key.IsPrivate = false
-		key.Key = data[45:78]
+		key.Key = payload[46:78]
+	case bytes.Equal(key.Version, PublicWalletVersion):
+		if prefix == 0x00 {
+			return nil, ErrKeyVersionMismatch
+		}
+		key.Key = payload[45:78]
+	default:
+		return nil, ErrKeyVersionUnknown
 	}
 
-	// validate checksum
-	cs1, err := checksum(data[0 : len(data)-4])
-	if err != nil {
+	if err := key.Validate(); err != nil {
 		return nil, err
 	}
 
-	cs2 := data[len(data)-4:]
-	for i := range cs1 {
-		if cs1[i] != cs2[i] {
-			return nil, ErrInvalidChecksum
+	return key, nil
+}
+
+// Validate checks the fields of key as bip-32 requires, the version should be
+// xprv or xpub of the key type, a private key should be in range [1, n-1] and
+// a public key should be a compressed point on the curve. Keys of depth 0 are
+// master keys, so their parent fingerprint and child number should be zero.
+func (key *Key) Validate() error {
+	expectVersion := PublicWalletVersion
+	if key.IsPrivate {
+		expectVersion = PrivateWalletVersion
+	}
+
+	if !bytes.Equal(key.Version, PrivateWalletVersion) && !bytes.Equal(key.Version, PublicWalletVersion) {
+		return ErrKeyVersionUnknown
+	}
+
+	if !bytes.Equal(key.Version, expectVersion) {
+		return ErrKeyVersionMismatch
+	}
+
+	if len(key.FingerPrint) != 4 || len(key.ChildNumber) != 4 || len(key.ChainCode) != 32 {
+		return ErrKeyFieldSize
+	}
+
+	if key.Depth == 0 && !bytes.Equal(key.FingerPrint, []byte{0, 0, 0, 0}) {
+		return ErrZeroDepthFingerprint
+	}
+
+	if key.Depth == 0 && !bytes.Equal(key.ChildNumber, []byte{0, 0, 0, 0}) {
+		return ErrZeroDepthChildNumber
+	}
+
+	if key.IsPrivate {
+		k := new(big.Int).SetBytes(key.Key)
+		if len(key.Key) != 32 || k.Sign() == 0 || k.Cmp(secp256k1.Params().N) >= 0 {
+			return ErrPrivateKeyOutOfRange
 		}
+
+		return nil
 	}
-	return key, nil
+
+	if len(key.Key) != PublicKeyCompressedLength || (key.Key[0] != 0x02 && key.Key[0] != 0x03) {
+		return ErrPublicKeyPrefix
+	}
+
+	if x, _ := UnmarshalCompressed(secp256k1, key.Key); x == nil {
+		return ErrInvalidPublicKey
+	}
+
+	return nil
 }
 
+// Base58Decode decodes and validates the extended key, see Deserialize.
 func Base58Decode(data string) (*Key, error) {
 	b, err := b58Decode(data)
 	if err != nil {
@@ -1,7 +1,11 @@
 package mderive
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
 	"testing"
 )
 
@@ -76,3 +80,311 @@ func TestDeserialize(t *testing.T) {
 		return
 	}
 }
+
+// test vectors 1 to 4 of bip-32
+var bip32Vectors = []struct {
+	seed   string
+	chains []struct{ path, xpub, xprv string }
+}{
+	{
+		"000102030405060708090a0b0c0d0e0f",
+		[]struct{ path, xpub, xprv string }{
+			{
+				"m",
+				"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+				"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			},
+			{
+				"m/0H",
+				"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+				"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			},
+			{
+				"m/0H/1",
+				"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+				"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			},
+			{
+				"m/0H/1/2H",
+				"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+				"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+			},
+			{
+				"m/0H/1/2H/2",
+				"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+				"xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+			},
+			{
+				"m/0H/1/2H/2/1000000000",
+				"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+				"xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+			},
+		},
+	},
+	{
+		"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		[]struct{ path, xpub, xprv string }{
+			{
+				"m",
+				"xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+				"xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+			},
+			{
+				"m/0",
+				"xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+				"xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+			},
+			{
+				"m/0/2147483647H",
+				"xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+				"xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+			},
+			{
+				"m/0/2147483647H/1",
+				"xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+				"xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+			},
+			{
+				"m/0/2147483647H/1/2147483646H",
+				"xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+				"xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+			},
+			{
+				"m/0/2147483647H/1/2147483646H/2",
+				"xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+				"xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+			},
+		},
+	},
+	{
+		// leading zeros of private keys are retained
+		"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		[]struct{ path, xpub, xprv string }{
+			{
+				"m",
+				"xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+				"xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+			},
+			{
+				"m/0H",
+				"xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+				"xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+			},
+		},
+	},
+	{
+		// leading zeros of private keys are retained
+		"3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678",
+		[]struct{ path, xpub, xprv string }{
+			{
+				"m",
+				"xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+				"xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+			},
+			{
+				"m/0H",
+				"xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+				"xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+			},
+			{
+				"m/0H/1H",
+				"xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+				"xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+			},
+		},
+	},
+}
+
+func TestBIP32Vectors(t *testing.T) {
+	for _, vector := range bip32Vectors {
+		seed, _ := hex.DecodeString(vector.seed)
+		master, err := NewMasterKey(seed)
+		if err != nil {
+			t.Fatalf("seed to master key failed: %s", err)
+		}
+
+		for _, chain := range vector.chains {
+			key, err := DerivePrivateKey(master, chain.path)
+			if err != nil {
+				t.Fatalf("derive %s failed: %s", chain.path, err)
+			}
+
+			if key.String() != chain.xprv || key.PublicKey().String() != chain.xpub {
+				t.Fatalf("derive wrong key at %s of seed %s", chain.path, vector.seed)
+			}
+
+			for _, encoded := range []string{chain.xprv, chain.xpub} {
+				decoded, err := Base58Decode(encoded)
+				if err != nil || decoded.String() != encoded {
+					t.Fatalf("decode %s failed: %v", encoded, err)
+				}
+			}
+		}
+	}
+}
+
+func TestDeserializeInvalidKeys(t *testing.T) {
+	// test vector 5 of bip-32
+	cases := []struct {
+		key string
+		err error
+	}{
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm", ErrKeyVersionMismatch},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH", ErrKeyVersionMismatch},
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn", ErrPublicKeyPrefix},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ", ErrPrivateKeyPrefix},
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4", ErrPublicKeyPrefix},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J", ErrPrivateKeyPrefix},
+		{"xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv", ErrZeroDepthFingerprint},
+		{"xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ", ErrZeroDepthFingerprint},
+		{"xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN", ErrZeroDepthChildNumber},
+		{"xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8", ErrZeroDepthChildNumber},
+		{"DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4", ErrKeyVersionUnknown},
+		{"DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHPmHJiEDXkTiJTVV9rHEBUem2mwVbbNfvT2MTcAqj3nesx8uBf9", ErrKeyVersionUnknown},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx", ErrPrivateKeyOutOfRange},
+		{"xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G", ErrPrivateKeyOutOfRange},
+		{"xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY", ErrInvalidPublicKey},
+		{"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL", ErrInvalidChecksum},
+	}
+
+	for _, c := range cases {
+		if _, err := Base58Decode(c.key); err != c.err {
+			t.Fatalf("decode %s should fail by %v: %v", c.key, c.err, err)
+		}
+	}
+}
+
+func TestDeserializeStrict(t *testing.T) {
+	b58 := "xprv9s21ZrQH143K32mLvbGNtzZsMdpY2bHJo5v5Eh6yxgbSLYHWT8kj7imgkDh7WjCByYsmu18vZcqTjbUVP2HxNMqGNqKdWM76PvxiYzYJ5hK"
+	data, _ := b58Decode(b58)
+
+	// checksum is verified before the unknown version is read
+	modified := bytes.Clone(data)
+	modified[0] = 0x01
+	if _, err := Deserialize(modified); err != ErrInvalidChecksum {
+		t.Fatalf("checksum should be verified first: %v", err)
+	}
+
+	if _, err := Deserialize(data[:81]); err != ErrSerializedKeyWrongSize {
+		t.Fatalf("short data should be rejected: %v", err)
+	}
+
+	key, err := Deserialize(data)
+	if err != nil {
+		t.Fatalf("deserialize key failed: %s", err)
+	}
+
+	// decoded key doesn't share memory with data
+	data[50] ^= 0xff
+	if key.String() != b58 {
+		t.Fatalf("decoded key changed with data")
+	}
+
+	if _, err := Base58Decode("xprv0"); !errors.Is(err, ErrInvalidBase58) {
+		t.Fatalf("invalid base58 character should be rejected: %v", err)
+	}
+
+	public := key.PublicKey()
+	public.Key = append([]byte{0x04}, public.Key[1:]...)
+	if err := public.Validate(); err != ErrPublicKeyPrefix {
+		t.Fatalf("public key prefix should be validated: %v", err)
+	}
+
+	child, _ := key.NewChild(0)
+	child.Depth = 0
+	if err := child.Validate(); err != ErrZeroDepthFingerprint {
+		t.Fatalf("depth of child key should be validated: %v", err)
+	}
+
+	child, _ = key.NewChild(0)
+	child.Version = PublicWalletVersion
+	if err := child.Validate(); err != ErrKeyVersionMismatch {
+		t.Fatalf("version of private key should be validated: %v", err)
+	}
+}
+
+func TestChildFromIntermediary(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, _ := NewMasterKey(seed)
+	public := master.PublicKey()
+
+	n := secp256k1.Params().N
+	k := new(big.Int).SetBytes(master.Key)
+	chainCode := bytes.Repeat([]byte{0x01}, 32)
+
+	// forced I = IL || IR, IL is padded to 32 bytes
+	intermediary := func(il *big.Int) []byte {
+		return append(il.FillBytes(make([]byte, 32)), chainCode...)
+	}
+
+	invalid := []struct {
+		key *Key
+		il  *big.Int
+	}{
+		{master, n}, // IL >= n
+		{master, new(big.Int).Add(n, big.NewInt(1))}, // IL >= n
+		{master, new(big.Int).Sub(n, k)},             // k + IL = 0 mod n
+		{public, n},                                  // IL >= n
+		{public, new(big.Int).Sub(n, k)},             // point(IL) + K is infinity
+	}
+
+	for _, c := range invalid {
+		if _, err := c.key.childFromIntermediary(0, intermediary(c.il)); err != ErrInvalidChildKey {
+			t.Fatalf("child of IL %x should be invalid: %v", c.il, err)
+		}
+	}
+
+	// IL = 0 is valid and keeps the parent key
+	child, err := master.childFromIntermediary(0, intermediary(big.NewInt(0)))
+	if err != nil || !bytes.Equal(child.Key, master.Key) {
+		t.Fatalf("private child of IL 0 should keep the key: %v", err)
+	}
+
+	publicChild, err := public.childFromIntermediary(0, intermediary(big.NewInt(0)))
+	if err != nil || publicChild.String() != child.PublicKey().String() {
+		t.Fatalf("public child of IL 0 should match private child: %v", err)
+	}
+
+	// IL = n - 1 is the largest valid value
+	il := new(big.Int).Sub(n, big.NewInt(1))
+	child, _ = master.childFromIntermediary(0, intermediary(il))
+	publicChild, err = public.childFromIntermediary(0, intermediary(il))
+	if err != nil || publicChild.String() != child.PublicKey().String() {
+		t.Fatalf("public child of IL n-1 should match private child: %v", err)
+	}
+}
+
+func TestNextValidChild(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, _ := NewMasterKey(seed)
+
+	child, index, err := master.NextValidChild(FirstHardenedChild)
+	expect, _ := master.NewChild(FirstHardenedChild)
+	if err != nil || index != FirstHardenedChild || child.String() != expect.String() {
+		t.Fatalf("valid child should be derived at the same index: %v", err)
+	}
+
+	// children at index 5 and 6 are forced to be invalid
+	newChild := func(childIndex uint32) (*Key, error) {
+		if childIndex == 5 || childIndex == 6 {
+			return nil, ErrInvalidChildKey
+		}
+		return master.NewChild(childIndex)
+	}
+
+	child, index, err = nextValidChild(5, newChild)
+	expect, _ = master.NewChild(7)
+	if err != nil || index != 7 || child.String() != expect.String() {
+		t.Fatalf("invalid children should be skipped: %d, %v", index, err)
+	}
+
+	alwaysInvalid := func(uint32) (*Key, error) {
+		return nil, ErrInvalidChildKey
+	}
+
+	for _, start := range []uint32{FirstHardenedChild - 1, math.MaxUint32} {
+		if _, _, err := nextValidChild(start, alwaysInvalid); err != ErrChildIndexExhausted {
+			t.Fatalf("index %d should not cross the range: %v", start, err)
+		}
+	}
+}
@@ -1,13 +1,14 @@
 package mderive
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/binary"
-	"fmt"
-	"github.io/decision2016/go-derived-mnemonic/wordlists"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+	"io"
 	"math/big"
 	"strings"
 )
@@ -36,39 +37,78 @@ var (
 )
 
 var (
-	wordList             []string
-	wordMap              map[string]int
 	validMnemonicLengths = []int{12, 15, 18, 21, 24}
 )
 
 func NewEntropy(bitSize int) ([]byte, error) {
+	return NewEntropyFromReader(rand.Reader, bitSize)
+}
+
+// NewEntropyFromReader reads entropy from source instead of crypto/rand, e.g.
+// a hardware RNG wrapped by EntropySource to run health tests on it.
+func NewEntropyFromReader(source io.Reader, bitSize int) ([]byte, error) {
 	if err := checkEntropyBitsLength(bitSize); err != nil {
 		return nil, err
 	}
 
 	newEntropy := make([]byte, bitSize/8)
-	if _, err := rand.Read(newEntropy); err != nil {
+	if _, err := io.ReadFull(source, newEntropy); err != nil {
 		return nil, err
 	}
 
 	return newEntropy, nil
 }
 
-func NewMnemonic(length int) (string, error) {
+func NewMnemonic(length int, lang ...Language) (string, error) {
+	return NewMnemonicFromReader(rand.Reader, length, lang...)
+}
+
+// NewMnemonicFromReader creates new mnemonic by entropy read from source.
+func NewMnemonicFromReader(source io.Reader, length int, lang ...Language) (string, error) {
+	entropyBitLength, err := EntropyBitSize(length)
+	if err != nil {
+		return "", err
+	}
+
+	entropy, err := NewEntropyFromReader(source, entropyBitLength)
+	if err != nil {
+		return "", err
+	}
+
+	return NewMnemonicByEntropy(entropy, lang...)
+}
+
+// EntropyBitSize returns the entropy bits of mnemonic with length words, e.g.
+// 128 bits for 12 words, length must be 12, 15, 18, 21 or 24.
+func EntropyBitSize(length int) (int, error) {
 	if !CheckInArr(validMnemonicLengths, length) {
-		return "", ErrMnemonicLengthInvalid
+		return 0, ErrMnemonicLengthInvalid
 	}
 
-	entropyBitLength := length*11 - length/3
-	entropy, err := NewEntropy(entropyBitLength)
+	return length*11 - length/3, nil
+}
+
+func NewMnemonicByEntropy(entropy []byte, lang ...Language) (string, error) {
+	wl, err := getWordlist(lang)
 	if err != nil {
 		return "", err
 	}
 
-	return NewMnemonicByEntropy(entropy)
+	indices, err := entropyToIndices(entropy)
+	if err != nil {
+		return "", err
+	}
+
+	words := make([]string, len(indices))
+	for i, index := range indices {
+		words[i] = wl.Word(index)
+	}
+
+	return wl.Join(words), nil
 }
 
-func NewMnemonicByEntropy(entropy []byte) (string, error) {
+// entropyToIndices returns wordlist indices of the mnemonic words of entropy
+func entropyToIndices(entropy []byte) ([]int, error) {
 	// bip-39 step. 2
 	// calculate entropy length, checksum length and mnemonic length = (entropy + checksum) / 11
 	entropyBitsLength := len(entropy) * 8
@@ -76,7 +116,7 @@ func NewMnemonicByEntropy(entropy []byte) (string, error) {
 	mnemonicLength := (entropyBitsLength + checkBitsLength) / 11
 
 	if err := checkEntropyBitsLength(entropyBitsLength); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	entropy = addChecksum(entropy)
@@ -84,7 +124,7 @@ func NewMnemonicByEntropy(entropy []byte) (string, error) {
 	entropyInt := new(big.Int)
 	entropyInt.SetBytes(entropy)
 
-	words := make([]string, mnemonicLength)
+	indices := make([]int, mnemonicLength)
 	word := big.NewInt(0)
 
 	for i := mnemonicLength - 1; i >= 0; i-- {
@@ -93,24 +133,112 @@ func NewMnemonicByEntropy(entropy []byte) (string, error) {
 
 		wordBytes := paddingZero(word.Bytes(), 2)
 
-		words[i] = wordList[binary.BigEndian.Uint16(wordBytes)]
+		indices[i] = int(binary.BigEndian.Uint16(wordBytes))
 	}
 
-	return strings.Join(words, " "), nil
+	return indices, nil
 }
 
-func EntropyFromMnemonic(mnemonic []string) ([]byte, error) {
+// EntropyFromMnemonic decodes mnemonic to entropy, the wordlist is detected
+// automatically if language is not given. Words can be abbreviated to their
+// unique prefix, e.g. the first four letters of english words.
+func EntropyFromMnemonic(mnemonic []string, lang ...Language) ([]byte, error) {
+	entropy, _, err := decodeMnemonic(mnemonic, lang)
+	return entropy, err
+}
+
+// decodeMnemonic returns entropy of mnemonic and the wordlist it decoded by
+func decodeMnemonic(mnemonic []string, lang []Language) ([]byte, *Wordlist, error) {
 	if !CheckInArr(validMnemonicLengths, len(mnemonic)) {
-		return nil, ErrMnemonicLengthInvalid
+		return nil, nil, ErrMnemonicLengthInvalid
 	}
 
+	if len(lang) > 0 {
+		wl, err := lang[0].Wordlist()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entropy, err := entropyFromMnemonic(mnemonic, wl)
+		return entropy, wl, err
+	}
+
+	matched, closest := detectLanguage(mnemonic)
+	if len(matched) == 0 {
+		// report the unknown word in the most similar wordlist
+		entropy, err := entropyFromMnemonic(mnemonic, closest)
+		return entropy, closest, err
+	}
+
+	var entropy []byte
+	var entropyWordlist *Wordlist
+	var firstErr error
+
+	for _, wl := range matched {
+		candidateEntropy, err := entropyFromMnemonic(mnemonic, wl)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if entropy != nil && !bytes.Equal(entropy, candidateEntropy) {
+			return nil, nil, ErrLanguageAmbiguous
+		}
+
+		if entropy == nil {
+			entropy, entropyWordlist = candidateEntropy, wl
+		}
+	}
+
+	if entropy == nil {
+		return nil, nil, firstErr
+	}
+
+	return entropy, entropyWordlist, nil
+}
+
+// ExpandMnemonic expands abbreviated words of mnemonic to the canonical words,
+// the wordlist is detected automatically if language is not given.
+func ExpandMnemonic(mnemonic string, lang ...Language) (string, error) {
+	words := strings.Fields(mnemonic)
+
+	if len(lang) > 0 {
+		wl, err := lang[0].Wordlist()
+		if err != nil {
+			return "", err
+		}
+
+		return expandWords(words, wl)
+	}
+
+	_, closest := detectLanguage(words)
+	return expandWords(words, closest)
+}
+
+func expandWords(words []string, wl *Wordlist) (string, error) {
+	expanded := make([]string, len(words))
+	for i, word := range words {
+		index, exists := wl.Find(word)
+		if !exists {
+			return "", wl.wordNotFound(word, i)
+		}
+
+		expanded[i] = wl.Word(index)
+	}
+
+	return wl.Join(expanded), nil
+}
+
+func entropyFromMnemonic(mnemonic []string, wl *Wordlist) ([]byte, error) {
 	var wordBytes [2]byte
 	var b = big.NewInt(0)
 
-	for _, word := range mnemonic {
-		index, exists := wordMap[word]
+	for position, word := range mnemonic {
+		index, exists := wl.Find(word)
 		if !exists {
-			return nil, fmt.Errorf("word %s not exists in wordlist", word)
+			return nil, wl.wordNotFound(word, position)
 		}
 
 		binary.BigEndian.PutUint16(wordBytes[:], uint16(index))
@@ -168,22 +296,20 @@ func MnemonicToByteArray(mnemonic string, raw ...bool) ([]byte, error) {
 	return paddingZero(addChecksum(entropy), fullByteSize), nil
 }
 
-func NewSeedWithErrorCheck(mnemonic string, passphrase string) ([]byte, error) {
-	words := strings.Fields(mnemonic)
-	mnemonicWithSpace := strings.Join(words, " ")
-
-	_, err := MnemonicToByteArray(mnemonicWithSpace)
+func NewSeedWithErrorCheck(mnemonic string, passphrase string, lang ...Language) ([]byte, error) {
+	m, err := ParseMnemonic(mnemonic, lang...)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewSeedByMnemonic(mnemonicWithSpace, passphrase), nil
+	return m.Seed(passphrase)
 }
 
+// NewSeedByMnemonic converts mnemonic to seed, both mnemonic and salt are NFKD
+// normalized as bip-39 required.
 func NewSeedByMnemonic(mnemonic string, passphrase string) []byte {
-	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
-}
+	password := norm.NFKD.String(mnemonic)
+	salt := norm.NFKD.String("mnemonic" + passphrase)
 
-func init() {
-	setupWordList(wordlists.English)
+	return pbkdf2.Key([]byte(password), []byte(salt), 2048, 64, sha512.New)
 }
@@ -1,43 +1,25 @@
 package mderive
 
-import (
-	"strconv"
-	"strings"
-)
-
+// DerivePrivateKey derives the key at path from key, see ParseDerivationPath
+// for the syntax of path.
 func DerivePrivateKey(key *Key, path string) (*Key, error) {
-	points := strings.Split(path, "/")
-	if len(points) <= 0 || points[0] != "m" {
-		return nil, ErrDerivationPathInvalid
+	derivationPath, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	points = points[1:]
-	var keyPoint = key
-	for _, point := range points {
-		flag := false
-
-		code := uint32(0x80000000)
-		if point[len(point)-1:] == "'" {
-			point = point[:len(point)-1]
-			flag = true
-		}
-
-		pointNum, err := strconv.ParseInt(point, 10, 32)
-		if err != nil {
-			return nil, ErrDerivationPathInvalid
-		}
-
-		if flag {
-			code = code | uint32(pointNum)
-		} else {
-			code = uint32(pointNum)
-		}
+	return derivationPath.Derive(key)
+}
 
-		keyPoint, err = keyPoint.NewChild(code)
-		if err != nil {
-			return nil, err
-		}
+// DerivePublicKey derives the public key at path from key, which may be a
+// private key or an extended public key from Base58Decode. Public keys can
+// only be derived along unhardened indices, a hardened segment fails by
+// *PathError wrapping ErrHardnedChildPublicKey.
+func DerivePublicKey(key *Key, path string) (*Key, error) {
+	derivationPath, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	return keyPoint, nil
+	return derivationPath.Derive(key.PublicKey())
 }
@@ -2,6 +2,7 @@ package mderive
 
 import (
 	"encoding/hex"
+	"errors"
 	"testing"
 )
 
@@ -32,3 +33,75 @@ func TestDerivePrivateKey(t *testing.T) {
 		return
 	}
 }
+
+func TestDerivePublicKey(t *testing.T) {
+	// xpub-only chains of bip-32 test vectors 1 and 2
+	cases := []struct {
+		parent string
+		path   string
+		expect string
+	}{
+		{
+			"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+			"1",
+			"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+		{
+			"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+			"2",
+			"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+		},
+		{
+			"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+			"2/1000000000",
+			"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+		},
+		{
+			"xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+			"m/0",
+			"xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+		},
+		{
+			"xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+			"1",
+			"xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+		},
+		{
+			"xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+			"2",
+			"xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+		},
+	}
+
+	for _, c := range cases {
+		parent, err := Base58Decode(c.parent)
+		if err != nil {
+			t.Fatalf("decode xpub failed: %s", err)
+		}
+
+		key, err := DerivePublicKey(parent, c.path)
+		if err != nil {
+			t.Fatalf("derive public key %s failed: %s", c.path, err)
+		}
+
+		if key.String() != c.expect {
+			t.Fatalf("derive wrong public key at %s: %s", c.path, key)
+		}
+	}
+
+	// private parent derives the same public key as its xpub
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, _ := NewMasterKey(seed)
+	account, _ := DerivePrivateKey(master, "m/0'/1/2'")
+	key, err := DerivePublicKey(account, "2/1000000000")
+	if err != nil || key.String() != cases[2].expect {
+		t.Fatalf("derive public key from private key failed: %v", err)
+	}
+
+	_, err = DerivePublicKey(master, "m/0/1'/2")
+
+	var pathErr *PathError
+	if !errors.As(err, &pathErr) || pathErr.Segment != 1 || pathErr.Err != ErrHardnedChildPublicKey {
+		t.Fatalf("hardened segment should fail at segment 1: %v", err)
+	}
+}
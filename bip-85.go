@@ -3,17 +3,322 @@ package mderive
 import (
 	"crypto/hmac"
 	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"io"
+	"math/bits"
 )
 
+// bip-85 application numbers
+const (
+	BIP85Purpose = 83696968
+
+	AppBIP39     = 39
+	AppHDSeedWIF = 2
+	AppXPRV      = 32
+	AppHex       = 128169
+	AppPWDBase64 = 707764
+	AppPWDBase85 = 707785
+	AppDice      = 89101
+)
+
+// maxPathNumber is the maximum number of a hardened path segment
+const maxPathNumber = FirstHardenedChild - 1
+
+// LegacyMnemonicBasePath is the base path of mnemonics derived by the derive
+// command before it followed the BIP39 application of bip-85, child mnemonics
+// are derived from the unhardened path {base}/{index} in english.
+const LegacyMnemonicBasePath = "m/83696968'/0'/0'"
+
+// base85 alphabet of RFC 1924, which is used by python base64.b85encode
+const base85Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~"
+
 func DeriveEntropyForMnemonic(key *Key, path string) ([]byte, error) {
 	derivedKey, err := DerivePrivateKey(key, path)
 	if err != nil {
 		return nil, err
 	}
 
+	return entropyFromKey(derivedKey), nil
+}
+
+// entropyFromKey returns the bip-85 entropy of the derived private key
+func entropyFromKey(derivedKey *Key) []byte {
 	h := hmac.New(sha512.New, []byte("bip-entropy-from-k"))
 	h.Write(derivedKey.Key)
-	output := h.Sum(nil)
+	return h.Sum(nil)
+}
+
+// BIP85Path returns the hardened path m/83696968'/{app}'/{params...}' of a
+// bip-85 application, every number should be less than 2^31.
+func BIP85Path(app uint32, params ...uint32) (string, error) {
+	if app > maxPathNumber {
+		return "", ErrDerivationPathInvalid
+	}
+
+	path := fmt.Sprintf("m/%d'/%d'", BIP85Purpose, app)
+	for _, param := range params {
+		if param > maxPathNumber {
+			return "", ErrDerivationPathInvalid
+		}
+		path += fmt.Sprintf("/%d'", param)
+	}
+
+	return path, nil
+}
+
+// MnemonicPath returns the path m/83696968'/39'/{language}'/{words}'/{index}'
+// of the BIP39 application, which is used by Coldcard, Sparrow and SeedSigner.
+func MnemonicPath(lang Language, words int, index uint32) (string, error) {
+	if !CheckInArr([]int{12, 15, 18, 21, 24}, words) {
+		return "", ErrMnemonicLengthInvalid
+	}
+
+	if lang < English || lang >= firstCustomLanguage {
+		return "", ErrLanguageUnsupported
+	}
+
+	return BIP85Path(AppBIP39, uint32(lang), uint32(words), index)
+}
+
+// deriveAppEntropy derives entropy from the hardened path m/83696968'/{app}'/{params...}'
+func deriveAppEntropy(key *Key, app uint32, params ...uint32) ([]byte, error) {
+	path, err := BIP85Path(app, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeriveEntropyForMnemonic(key, path)
+}
+
+// DeriveMnemonic derives a bip-39 mnemonic of words length and language by the
+// BIP39 application, see MnemonicPath.
+func DeriveMnemonic(key *Key, lang Language, words int, index uint32) (string, error) {
+	path, err := MnemonicPath(lang, words, index)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := lang.Wordlist(); err != nil {
+		return "", err
+	}
+
+	entropy, err := DeriveEntropyForMnemonic(key, path)
+	if err != nil {
+		return "", err
+	}
+
+	return NewMnemonicByEntropy(entropy[:words*4/3], lang)
+}
+
+// DeriveLegacyMnemonic derives an english mnemonic from the unhardened path
+// {basePath}/{index} as the derive command did before bip-85 paths, basePath
+// is usually LegacyMnemonicBasePath. Only use it to reproduce existing mnemonics.
+func DeriveLegacyMnemonic(key *Key, basePath string, words int, index uint32) (string, error) {
+	if !CheckInArr([]int{12, 15, 18, 21, 24}, words) {
+		return "", ErrMnemonicLengthInvalid
+	}
+
+	if index > maxPathNumber {
+		return "", ErrDerivationPathInvalid
+	}
+
+	entropy, err := DeriveEntropyForMnemonic(key, fmt.Sprintf("%s/%d", basePath, index))
+	if err != nil {
+		return "", err
+	}
+
+	return NewMnemonicByEntropy(entropy[:words*4/3], English)
+}
+
+// DeriveWIF derives a compressed private key in wallet import format for
+// bitcoin core by the HD-Seed WIF application, path is m/83696968'/2'/{index}'.
+func DeriveWIF(key *Key, index uint32) (string, error) {
+	entropy, err := deriveAppEntropy(key, AppHDSeedWIF, index)
+	if err != nil {
+		return "", err
+	}
+
+	data := append([]byte{0x80}, entropy[:32]...)
+	data = append(data, 0x01)
+
+	data, err = addChecksumToBytes(data)
+	if err != nil {
+		return "", err
+	}
+
+	return b58Encode(data), nil
+}
+
+// DeriveXPRV derives a master extended private key by the XPRV application,
+// path is m/83696968'/32'/{index}'. The first 32 bytes of entropy are the chain
+// code and the second 32 bytes are the private key. ErrPrivateKeyOutOfRange is
+// returned if the private key is 0 or not less than the curve order, try the
+// next index in that case.
+func DeriveXPRV(key *Key, index uint32) (*Key, error) {
+	entropy, err := deriveAppEntropy(key, AppXPRV, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return xprvFromEntropy(entropy)
+}
+
+func xprvFromEntropy(entropy []byte) (*Key, error) {
+	xprv := &Key{
+		Key:         entropy[32:],
+		Version:     PrivateWalletVersion,
+		ChildNumber: []byte{0x00, 0x00, 0x00, 0x00},
+		FingerPrint: []byte{0x00, 0x00, 0x00, 0x00},
+		ChainCode:   entropy[:32],
+		Depth:       0x0,
+		IsPrivate:   true,
+	}
+
+	if err := xprv.Validate(); err != nil {
+		return nil, ErrPrivateKeyOutOfRange
+	}
+
+	return xprv, nil
+}
+
+// DeriveHex derives numBytes bytes in range [16, 64] by the HEX application,
+// path is m/83696968'/128169'/{numBytes}'/{index}'.
+func DeriveHex(key *Key, numBytes int, index uint32) ([]byte, error) {
+	if numBytes < 16 || numBytes > 64 {
+		return nil, ErrBIP85ParamInvalid
+	}
+
+	entropy, err := deriveAppEntropy(key, AppHex, uint32(numBytes), index)
+	if err != nil {
+		return nil, err
+	}
+
+	return entropy[:numBytes], nil
+}
+
+// DerivePasswordBase64 derives a base64 password of length in range [20, 86]
+// by the PWD BASE64 application, path is m/83696968'/707764'/{length}'/{index}'.
+func DerivePasswordBase64(key *Key, length int, index uint32) (string, error) {
+	if length < 20 || length > 86 {
+		return "", ErrBIP85ParamInvalid
+	}
+
+	entropy, err := deriveAppEntropy(key, AppPWDBase64, uint32(length), index)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(entropy)[:length], nil
+}
+
+// DerivePasswordBase85 derives a base85 password of length in range [10, 80]
+// by the PWD BASE85 application, path is m/83696968'/707785'/{length}'/{index}'.
+func DerivePasswordBase85(key *Key, length int, index uint32) (string, error) {
+	if length < 10 || length > 80 {
+		return "", ErrBIP85ParamInvalid
+	}
+
+	entropy, err := deriveAppEntropy(key, AppPWDBase85, uint32(length), index)
+	if err != nil {
+		return "", err
+	}
+
+	return b85Encode(entropy)[:length], nil
+}
+
+// DeriveDice derives rolls of a dice with sides, each roll is in range [0, sides),
+// by the DICE application, path is m/83696968'/89101'/{sides}'/{rolls}'/{index}'.
+func DeriveDice(key *Key, sides, rolls int, index uint32) ([]int, error) {
+	if sides < 2 || rolls < 1 || uint64(sides) > uint64(maxPathNumber) || uint64(rolls) > uint64(maxPathNumber) {
+		return nil, ErrBIP85ParamInvalid
+	}
+
+	entropy, err := deriveAppEntropy(key, AppDice, uint32(sides), uint32(rolls), index)
+	if err != nil {
+		return nil, err
+	}
+
+	bitsPerRoll := bits.Len(uint(sides - 1))
+	bytesPerRoll := (bitsPerRoll + 7) / 8
+	drng, err := NewDRNG(entropy)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, bytesPerRoll)
+
+	result := make([]int, 0, rolls)
+	for len(result) < rolls {
+		if _, err := io.ReadFull(drng, buffer); err != nil {
+			return nil, err
+		}
+
+		trial := uint64(0)
+		for _, b := range buffer {
+			trial = trial<<8 | uint64(b)
+		}
+		trial >>= bytesPerRoll*8 - bitsPerRoll
+
+		if trial < uint64(sides) {
+			result = append(result, int(trial))
+		}
+	}
+
+	return result, nil
+}
+
+// DRNG is the BIP85-DRNG, a SHAKE256 stream seeded by 64 bytes bip-85 entropy,
+// it produces deterministic output of any length.
+type DRNG struct {
+	shake sha3.ShakeHash
+}
+
+// NewDRNG returns the BIP85-DRNG seeded by entropy, the entropy should be the
+// 64 bytes returned by DeriveEntropyForMnemonic.
+func NewDRNG(entropy []byte) (*DRNG, error) {
+	if len(entropy) != 64 {
+		return nil, ErrDRNGEntropyInvalid
+	}
+
+	shake := sha3.NewShake256()
+	_, _ = shake.Write(entropy)
+
+	return &DRNG{shake: shake}, nil
+}
+
+// NewDRNGFromPath returns the BIP85-DRNG seeded by the entropy derived from path.
+func NewDRNGFromPath(key *Key, path string) (*DRNG, error) {
+	entropy, err := DeriveEntropyForMnemonic(key, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDRNG(entropy)
+}
+
+// Read fills p with the next bytes of stream, it never fails.
+func (d *DRNG) Read(p []byte) (int, error) {
+	return d.shake.Read(p)
+}
+
+// b85Encode encodes data like python base64.b85encode
+func b85Encode(data []byte) string {
+	padding := (4 - len(data)%4) % 4
+	padded := append(append([]byte{}, data...), make([]byte, padding)...)
+
+	output := make([]byte, 0, len(padded)/4*5)
+	for i := 0; i < len(padded); i += 4 {
+		value := uint32(padded[i])<<24 | uint32(padded[i+1])<<16 | uint32(padded[i+2])<<8 | uint32(padded[i+3])
+
+		var chunk [5]byte
+		for j := 4; j >= 0; j-- {
+			chunk[j] = base85Alphabet[value%85]
+			value /= 85
+		}
+		output = append(output, chunk[:]...)
+	}
 
-	return output, nil
+	return string(output[:len(output)-padding])
 }
@@ -2,6 +2,9 @@ package mderive
 
 import (
 	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +29,204 @@ func TestDeriveEntropy(t *testing.T) {
 		return
 	}
 }
+
+const bip85MasterKey = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+func TestDeriveEntropyVectors(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	entropy, err := DeriveEntropyForMnemonic(key, "m/83696968'/0'/1'")
+	if err != nil {
+		t.Fatalf("derive key to entropy failed: %s", err)
+	}
+
+	expect := "70c6e3e8ebee8dc4c0dbba66076819bb8c09672527c4277ca8729532ad711872218f826919f6b67218adde99018a6df9095ab2b58d803b5b93ec9802085a690e"
+	if hex.EncodeToString(entropy) != expect {
+		t.Fatalf("get wrong entropy from master key")
+	}
+}
+
+func TestDeriveMnemonic(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	tests := []struct {
+		words  int
+		expect string
+	}{
+		{12, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+		{18, "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token"},
+		{24, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano"},
+	}
+
+	for _, test := range tests {
+		mnemonic, err := DeriveMnemonic(key, English, test.words, 0)
+		if err != nil {
+			t.Fatalf("derive %d words mnemonic failed: %s", test.words, err)
+		}
+
+		if mnemonic != test.expect {
+			t.Fatalf("derive wrong %d words mnemonic: %s", test.words, mnemonic)
+		}
+	}
+
+	if _, err := DeriveMnemonic(key, English, 13, 0); err != ErrMnemonicLengthInvalid {
+		t.Fatalf("mnemonic with invalid length should be rejected")
+	}
+}
+
+func TestDeriveWIF(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	wif, err := DeriveWIF(key, 0)
+	if err != nil {
+		t.Fatalf("derive wif failed: %s", err)
+	}
+
+	if wif != "Kzyv4uF39d4Jrw2W7UryTHwZr1zQVNk4dAFyqE6BuMrMh1Za7uhp" {
+		t.Fatalf("derive wrong wif: %s", wif)
+	}
+}
+
+func TestDeriveXPRV(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	xprv, err := DeriveXPRV(key, 0)
+	if err != nil {
+		t.Fatalf("derive xprv failed: %s", err)
+	}
+
+	expect := "xprv9s21ZrQH143K2srSbCSg4m4kLvPMzcWydgmKEnMmoZUurYuBuYG46c6P71UGXMzmriLzCCBvKQWBUv3vPB3m1SATMhp3uEjXHJ42jFg7myX"
+	if xprv.Base58Encode() != expect {
+		t.Fatalf("derive wrong xprv: %s", xprv.Base58Encode())
+	}
+
+	for _, privateKey := range []string{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	} {
+		entropy, _ := hex.DecodeString(strings.Repeat("01", 32) + privateKey)
+		if _, err := xprvFromEntropy(entropy); err != ErrPrivateKeyOutOfRange {
+			t.Fatalf("private key %s should be out of range: %v", privateKey, err)
+		}
+	}
+}
+
+func TestDeriveHex(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	data, err := DeriveHex(key, 64, 0)
+	if err != nil {
+		t.Fatalf("derive hex failed: %s", err)
+	}
+
+	expect := "492db4698cf3b73a5a24998aa3e9d7fa96275d85724a91e71aa2d645442f878555d078fd1f1f67e368976f04137b1f7a0d19232136ca50c44614af72b5582a5c"
+	if hex.EncodeToString(data) != expect {
+		t.Fatalf("derive wrong hex: %x", data)
+	}
+
+	if _, err := DeriveHex(key, 15, 0); err != ErrBIP85ParamInvalid {
+		t.Fatalf("hex with invalid length should be rejected")
+	}
+}
+
+func TestDerivePassword(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	pwd, err := DerivePasswordBase64(key, 21, 0)
+	if err != nil || pwd != "dKLoepugzdVJvdL56ogNV" {
+		t.Fatalf("derive wrong base64 password: %s, %v", pwd, err)
+	}
+
+	pwd, err = DerivePasswordBase85(key, 12, 0)
+	if err != nil || pwd != "_s`{TW89)i4`" {
+		t.Fatalf("derive wrong base85 password: %s, %v", pwd, err)
+	}
+
+	if _, err := DerivePasswordBase64(key, 19, 0); err != ErrBIP85ParamInvalid {
+		t.Fatalf("base64 password with invalid length should be rejected")
+	}
+
+	if _, err := DerivePasswordBase85(key, 81, 0); err != ErrBIP85ParamInvalid {
+		t.Fatalf("base85 password with invalid length should be rejected")
+	}
+}
+
+func TestDeriveDice(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	rolls, err := DeriveDice(key, 6, 10, 0)
+	if err != nil {
+		t.Fatalf("derive dice failed: %s", err)
+	}
+
+	if fmt.Sprint(rolls) != "[1 0 0 2 0 1 5 5 2 4]" {
+		t.Fatalf("derive wrong dice rolls: %v", rolls)
+	}
+}
+
+func TestMnemonicPath(t *testing.T) {
+	path, err := MnemonicPath(Japanese, 18, 3)
+	if err != nil || path != "m/83696968'/39'/1'/18'/3'" {
+		t.Fatalf("build wrong mnemonic path: %s, %v", path, err)
+	}
+
+	if _, err := MnemonicPath(English, 13, 0); err != ErrMnemonicLengthInvalid {
+		t.Fatalf("path with invalid length should be rejected")
+	}
+
+	if _, err := MnemonicPath(English, 12, FirstHardenedChild); err != ErrDerivationPathInvalid {
+		t.Fatalf("path with hardened index should be rejected")
+	}
+}
+
+func TestDeriveLegacyMnemonic(t *testing.T) {
+	seed := NewSeedByMnemonic("used legend breeze program soldier position toddler limb long dinosaur urge hunt", "")
+	master, _ := NewMasterKey(seed)
+
+	mnemonic, err := DeriveLegacyMnemonic(master, LegacyMnemonicBasePath, 12, 1)
+	if err != nil {
+		t.Fatalf("derive legacy mnemonic failed: %s", err)
+	}
+
+	if mnemonic != "fade miracle siege logic menu beach stick barely cry gauge pair thunder" {
+		t.Fatalf("derive wrong legacy mnemonic: %s", mnemonic)
+	}
+
+	mnemonic, _ = DeriveMnemonic(master, English, 12, 1)
+	if mnemonic != "can armor law fall build damp cactus cancel size steel wash welcome" {
+		t.Fatalf("derive wrong bip-85 mnemonic: %s", mnemonic)
+	}
+}
+
+func TestDRNG(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	drng, err := NewDRNGFromPath(key, "m/83696968'/0'/0'")
+	if err != nil {
+		t.Fatalf("create drng failed: %s", err)
+	}
+
+	output := make([]byte, 80)
+	if _, err := io.ReadFull(drng, output); err != nil {
+		t.Fatalf("read drng failed: %s", err)
+	}
+
+	expect := "b78b1ee6b345eae6836c2d53d33c64cdaf9a696487be81b03e822dc84b3f1cd883d7559e53d175f243e4c349e822a957bbff9224bc5dde9492ef54e8a439f6bc8c7355b87a925a37ee405a7502991111"
+	if hex.EncodeToString(output) != expect {
+		t.Fatalf("drng output wrong bytes: %x", output)
+	}
+
+	// the stream continues across reads of any size
+	drng, _ = NewDRNGFromPath(key, "m/83696968'/0'/0'")
+	chunked := make([]byte, 80)
+	_, _ = io.ReadFull(drng, chunked[:7])
+	_, _ = io.ReadFull(drng, chunked[7:])
+	if hex.EncodeToString(chunked) != expect {
+		t.Fatalf("drng output wrong bytes in chunks: %x", chunked)
+	}
+
+	if _, err := NewDRNG(make([]byte, 32)); err != ErrDRNGEntropyInvalid {
+		t.Fatalf("drng with short entropy should be rejected")
+	}
+}
@@ -1,27 +1,51 @@
 package main
 
 import (
+	"bufio"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"strconv"
+	"strings"
 )
 
 var (
-	length int
+	length    int
+	language  string
+	dice      bool
+	diceSides int
+	diceMix   bool
 
 	deriveMnemonicLength int
 	deriveMnemonic       string
 	derivePassphrase     string
 	deriveBasePath       string
 	deriveCount          int
+	deriveLanguage       string
+	deriveLegacy         bool
 )
 
 var generate = &cobra.Command{
-	Use:   "new [--length | -l]",
+	Use:   "new [--length | -l] [--language | -g] [--dice] [--sides] [--mix]",
 	Short: "generate new mnemonic",
 	Long:  "generate new mnemonic",
 	Run: func(cmd *cobra.Command, args []string) {
-		mnemonic, err := mderive.NewMnemonic(length)
+		lang, err := mderive.ParseLanguage(language)
+		if err != nil {
+			fmt.Printf("parse mnemonic language failed: %s\n", err)
+			return
+		}
+
+		var mnemonic string
+		if dice {
+			mnemonic, err = newMnemonicByDice(length, lang)
+		} else {
+			mnemonic, err = mderive.NewMnemonicFromReader(mderive.NewEntropySource(rand.Reader), length, lang)
+		}
+
 		if err != nil {
 			fmt.Printf("create new mnemonic failed: %s\n", err)
 			return
@@ -33,18 +57,37 @@ var generate = &cobra.Command{
 }
 
 var derive = &cobra.Command{
-	Use:   "derive -m mnemonic [--path | -p] [--passphrase | -e] [--count | -n] ",
+	Use:   "derive -m mnemonic [--length | -l] [--language | -g] [--passphrase | -e] [--count | -n] [--legacy [--path | -p]]",
 	Short: "derive new mnemonics by main mnemonic",
-	Long:  "derive new mnemonics by main mnemonic",
+	Long: "derive new mnemonics by main mnemonic, child mnemonics follow the BIP39 application of bip-85 " +
+		"at m/83696968'/39'/{language}'/{length}'/{index}', use --legacy to reproduce mnemonics derived " +
+		"at {path}/{index} by previous versions",
 	Run: func(cmd *cobra.Command, args []string) {
 		if !mderive.CheckInArr([]int{12, 15, 18, 21, 24}, deriveMnemonicLength) {
 			fmt.Println("input wrong mnemonic length")
 			return
 		}
 
+		lang, err := mderive.ParseLanguage(deriveLanguage)
+		if err != nil {
+			fmt.Printf("parse mnemonic language failed: %s\n", err)
+			return
+		}
+
+		if !deriveLegacy && deriveBasePath != "" {
+			fmt.Println("custom base path is only supported by --legacy")
+			return
+		}
+
+		if deriveLegacy && lang != mderive.English {
+			fmt.Println("legacy mnemonics are always english")
+			return
+		}
+
 		seed, err := mderive.NewSeedWithErrorCheck(deriveMnemonic, derivePassphrase)
 		if err != nil {
 			fmt.Printf("decode mnemonic to seed failed: %s\n", err)
+			printWordSuggestions(err)
 			return
 		}
 
@@ -55,40 +98,125 @@ var derive = &cobra.Command{
 		}
 
 		basePath := deriveBasePath
-		results := make([]string, deriveCount)
-		bitsLength := deriveMnemonicLength*11 - deriveMnemonicLength/3
+		if basePath == "" {
+			basePath = mderive.LegacyMnemonicBasePath
+		}
 
-		for idx := 0; idx < deriveCount; idx++ {
-			path := fmt.Sprintf("%s/%d", basePath, idx)
-			entropy, err := mderive.DeriveEntropyForMnemonic(master, path)
+		var legacyPath mderive.DerivationPath
+		if deriveLegacy {
+			legacyPath, err = mderive.ParseDerivationPath(basePath)
 			if err != nil {
-				fmt.Printf("derive entropy for mnemonic failed: %s", err)
+				fmt.Printf("parse legacy base path failed: %s\n", err)
 				return
 			}
+		}
+
+		results := make([]string, deriveCount)
+		paths := make([]string, deriveCount)
+		for idx := 0; idx < deriveCount; idx++ {
+			var derivedMnemonic string
+			if deriveLegacy {
+				paths[idx] = legacyPath.Child(uint32(idx)).String()
+				derivedMnemonic, err = mderive.DeriveLegacyMnemonic(master, basePath, deriveMnemonicLength, uint32(idx))
+			} else {
+				paths[idx], err = mderive.MnemonicPath(lang, deriveMnemonicLength, uint32(idx))
+				if err == nil {
+					derivedMnemonic, err = mderive.DeriveMnemonic(master, lang, deriveMnemonicLength, uint32(idx))
+				}
+			}
 
-			derivedMnemonic, err := mderive.NewMnemonicByEntropy(entropy[:bitsLength/8])
 			if err != nil {
-				fmt.Printf("generate new mnemonic failed: %s", err)
+				fmt.Printf("derive new mnemonic failed: %s\n", err)
 				return
 			}
 
 			results[idx] = derivedMnemonic
 		}
 
-		fmt.Printf("derive %d new mnemonics based on path [%s]:\n", deriveCount, deriveBasePath)
+		if deriveLegacy {
+			fmt.Printf("derive %d new mnemonics by legacy path:\n", deriveCount)
+		} else {
+			fmt.Printf("derive %d new mnemonics by the BIP39 application of bip-85:\n", deriveCount)
+		}
+
 		for idx := 0; idx < deriveCount; idx++ {
-			fmt.Println(results[idx])
+			fmt.Printf("[%s] %s\n", paths[idx], results[idx])
 		}
 	},
 }
 
+// newMnemonicByDice prompts for dice rolls until they produce enough entropy
+func newMnemonicByDice(length int, lang mderive.Language) (string, error) {
+	bitSize, err := mderive.EntropyBitSize(length)
+	if err != nil {
+		return "", err
+	}
+
+	// rolls out of the unbiased range are discarded, so the minimum is only a lower bound
+	fmt.Printf("%d words mnemonic requires %d bits of entropy, which takes no less than %d rolls of a d%d dice, "+
+		"enter rolls separated by spaces until enough entropy is collected:\n",
+		length, bitSize, mderive.MinimumRolls(diceSides, bitSize), diceSides)
+
+	var rolls []int
+	scanner := bufio.NewScanner(os.Stdin)
+
+	for {
+		fmt.Printf("rolls (%d entered): ", len(rolls))
+		if !scanner.Scan() {
+			return "", mderive.ErrEntropyInsufficient
+		}
+
+		for _, field := range strings.Fields(scanner.Text()) {
+			roll, err := strconv.Atoi(field)
+			if err != nil || roll < 1 || roll > diceSides {
+				fmt.Printf("ignore invalid roll %s\n", field)
+				continue
+			}
+			rolls = append(rolls, roll)
+		}
+
+		entropy, err := mderive.EntropyFromDice(rolls, diceSides, bitSize)
+		if errors.Is(err, mderive.ErrEntropyInsufficient) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if diceMix {
+			entropy, err = mderive.MixEntropy(entropy)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return mderive.NewMnemonicByEntropy(entropy, lang)
+	}
+}
+
+// printWordSuggestions prints "did you mean" hints if err is caused by a misspelled word
+func printWordSuggestions(err error) {
+	var wordErr *mderive.WordNotFoundError
+	if !errors.As(err, &wordErr) || len(wordErr.Suggestions) == 0 {
+		return
+	}
+
+	fmt.Printf("did you mean %s for word #%d?\n", strings.Join(wordErr.Suggestions, ", "), wordErr.Position+1)
+}
+
 func init() {
 	generate.Flags().IntVarP(&length, "length", "l", 12, "mnemonic length, must be 12, 15, 18, 21 or 24")
+	generate.Flags().StringVarP(&language, "language", "g", "english", "mnemonic language, e.g. english, japanese or spanish")
+	generate.Flags().BoolVar(&dice, "dice", false, "generate entropy by dice rolls entered from stdin")
+	generate.Flags().IntVar(&diceSides, "sides", 6, "sides of the dice, e.g. 6 or 20")
+	generate.Flags().BoolVar(&diceMix, "mix", false, "mix dice entropy with system random by XOR")
 
 	derive.Flags().IntVarP(&deriveMnemonicLength, "length", "l", 12, "mnemonic length, must be 12, 15, 18, 21 or 24")
 	derive.Flags().StringVarP(&derivePassphrase, "passphrase", "e", "", "mnemonic need to be derived")
 	derive.Flags().StringVarP(&deriveMnemonic, "mnemonic", "m", "", "mnemonic passphrase")
-	derive.Flags().StringVarP(&deriveBasePath, "path", "p", "m/83696968'/0'/0'", "base derive path")
+	derive.Flags().StringVarP(&deriveBasePath, "path", "p", "", "base derive path of --legacy, default is m/83696968'/0'/0'")
+	derive.Flags().StringVarP(&deriveLanguage, "language", "g", "english", "language of derived mnemonics")
+	derive.Flags().BoolVar(&deriveLegacy, "legacy", false, "derive mnemonics at unhardened {path}/{index} as previous versions")
 	derive.Flags().IntVarP(&deriveCount, "count", "n", 1, "mnemonic derive count")
 
 	rootCmd.AddCommand(generate)
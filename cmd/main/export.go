@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"golang.org/x/term"
+	"os"
+	"strings"
+)
+
+var (
+	exportMnemonic string
+	exportLanguage string
+	importText     string
+
+	// stdin is shared by prompts, so a buffered line isn't lost between them
+	stdin = bufio.NewReader(os.Stdin)
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export -m mnemonic [--language | -g]",
+	Short: "encrypt mnemonic by password",
+	Long: "encrypt the entropy of mnemonic by password, the password is stretched by Argon2id and the entropy is " +
+		"sealed by XChaCha20-Poly1305. the text is checksummed and can be kept in password managers instead of words. " +
+		"the password is prompted twice and never taken from command line",
+	Run: func(cmd *cobra.Command, args []string) {
+		var langs []mderive.Language
+		if exportLanguage != "" {
+			lang, err := mderive.ParseLanguage(exportLanguage)
+			if err != nil {
+				fmt.Printf("parse mnemonic language failed: %s\n", err)
+				return
+			}
+			langs = append(langs, lang)
+		}
+
+		password, err := readPassword(true)
+		if err != nil {
+			fmt.Printf("read password failed: %s\n", err)
+			return
+		}
+
+		text, err := mderive.ExportMnemonic(exportMnemonic, password, langs...)
+		if err != nil {
+			fmt.Printf("export mnemonic failed: %s\n", err)
+			printWordSuggestions(err)
+			return
+		}
+
+		fmt.Println(text)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import -t text",
+	Short: "decrypt mnemonic exported by password",
+	Long:  "decrypt mnemonic exported by password, the password is prompted and never taken from command line",
+	Run: func(cmd *cobra.Command, args []string) {
+		password, err := readPassword(false)
+		if err != nil {
+			fmt.Printf("read password failed: %s\n", err)
+			return
+		}
+
+		mnemonic, err := mderive.ImportMnemonic(importText, password)
+		if err != nil {
+			fmt.Printf("import mnemonic failed: %s\n", err)
+			return
+		}
+
+		fmt.Println(mnemonic)
+	},
+}
+
+// readPassword prompts for password without echo, a line of stdin is read if
+// stdin isn't a terminal. the password is asked twice when confirm is set.
+func readPassword(confirm bool) (string, error) {
+	password, err := promptPassword("password: ")
+	if err != nil || !confirm {
+		return password, err
+	}
+
+	repeated, err := promptPassword("repeat password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if repeated != password {
+		return "", errors.New("passwords don't match")
+	}
+
+	return password, nil
+}
+
+func promptPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(password), err
+	}
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportMnemonic, "mnemonic", "m", "", "mnemonic to export")
+	exportCmd.Flags().StringVarP(&exportLanguage, "language", "g", "", "mnemonic language, detected automatically if not given")
+
+	importCmd.Flags().StringVarP(&importText, "text", "t", "", "exported text, starts with "+mderive.ExportPrefix)
+
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+)
+
+var (
+	keyMnemonic   string
+	keyPassphrase string
+	keyIndex      uint32
+
+	rsaBits    int
+	rsaGPG     bool
+	rsaName    string
+	rsaComment string
+	rsaEmail   string
+
+	sshComment string
+)
+
+var rsaKey = &cobra.Command{
+	Use:   "rsa-key -m mnemonic [--bits | -b] [--index | -i] [--passphrase | -e] [--gpg --name --email [--comment]]",
+	Short: "derive rsa or gpg key by master mnemonic",
+	Long: "derive rsa key by the RSA application of bip-85 at m/83696968'/828365'/{bits}'/{index}', " +
+		"the key is printed as PEM or as OpenPGP armored key by --gpg",
+	Run: func(cmd *cobra.Command, args []string) {
+		master, err := newMasterKey(keyMnemonic, keyPassphrase)
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		if !rsaGPG {
+			priv, err := mderive.DeriveRSA(master, rsaBits, keyIndex)
+			if err != nil {
+				fmt.Printf("derive rsa key failed: %s\n", err)
+				return
+			}
+
+			fmt.Print(string(mderive.MarshalRSAPrivateKey(priv)))
+			return
+		}
+
+		entity, err := mderive.DeriveGPGKey(master, rsaBits, keyIndex, rsaName, rsaComment, rsaEmail)
+		if err != nil {
+			fmt.Printf("derive gpg key failed: %s\n", err)
+			return
+		}
+
+		for _, private := range []bool{false, true} {
+			armored, err := mderive.ArmorGPGKey(entity, private)
+			if err != nil {
+				fmt.Printf("armor gpg key failed: %s\n", err)
+				return
+			}
+
+			fmt.Print(armored)
+		}
+	},
+}
+
+var sshKey = &cobra.Command{
+	Use:   "ssh-key -m mnemonic [--index | -i] [--passphrase | -e] [--comment | -c]",
+	Short: "derive ed25519 ssh key by master mnemonic",
+	Long:  "derive ed25519 ssh key at m/83696968'/6968'/{index}', the key is printed as OpenSSH private key and public key",
+	Run: func(cmd *cobra.Command, args []string) {
+		priv, err := deriveEd25519(keyMnemonic, keyPassphrase, keyIndex)
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		privateKey, err := mderive.MarshalOpenSSHPrivateKey(priv, sshComment)
+		if err != nil {
+			fmt.Printf("marshal ssh private key failed: %s\n", err)
+			return
+		}
+
+		publicKey, err := mderive.MarshalOpenSSHPublicKey(priv, sshComment)
+		if err != nil {
+			fmt.Printf("marshal ssh public key failed: %s\n", err)
+			return
+		}
+
+		fmt.Print(string(privateKey))
+		fmt.Println(publicKey)
+	},
+}
+
+var ageKey = &cobra.Command{
+	Use:   "age-key -m mnemonic [--index | -i] [--passphrase | -e]",
+	Short: "derive age x25519 identity by master mnemonic",
+	Long: "derive age x25519 identity from the ed25519 key at m/83696968'/6968'/{index}', " +
+		"the identity is printed in the format of age-keygen",
+	Run: func(cmd *cobra.Command, args []string) {
+		priv, err := deriveEd25519(keyMnemonic, keyPassphrase, keyIndex)
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		identity, recipient, err := mderive.AgeKeys(priv)
+		if err != nil {
+			fmt.Printf("convert age keys failed: %s\n", err)
+			return
+		}
+
+		fmt.Printf("# public key: %s\n", recipient)
+		fmt.Println(identity)
+	},
+}
+
+// deriveEd25519 derives the ed25519 key at index of master mnemonic
+func deriveEd25519(mnemonic, passphrase string, index uint32) (ed25519.PrivateKey, error) {
+	master, err := newMasterKey(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	priv, err := mderive.DeriveEd25519(master, index)
+	if err != nil {
+		return nil, fmt.Errorf("derive ed25519 key failed: %w", err)
+	}
+
+	return priv, nil
+}
+
+// newMasterKey decodes the master key of mnemonic with passphrase
+func newMasterKey(mnemonic, passphrase string) (*mderive.Key, error) {
+	seed, err := mderive.NewSeedWithErrorCheck(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decode mnemonic to seed failed: %w", err)
+	}
+
+	master, err := mderive.NewMasterKey(seed)
+	if err != nil {
+		return nil, fmt.Errorf("decode seed to master key failed: %w", err)
+	}
+
+	return master, nil
+}
+
+// addKeyFlags adds the flags of master mnemonic and index shared by key commands
+func addKeyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&keyMnemonic, "mnemonic", "m", "", "master mnemonic")
+	cmd.Flags().StringVarP(&keyPassphrase, "passphrase", "e", "", "master mnemonic passphrase")
+	cmd.Flags().Uint32VarP(&keyIndex, "index", "i", 0, "key index")
+}
+
+func init() {
+	addKeyFlags(rsaKey)
+	rsaKey.Flags().IntVarP(&rsaBits, "bits", "b", 2048, "rsa key size, a multiple of 256, e.g. 2048 or 4096")
+	rsaKey.Flags().BoolVar(&rsaGPG, "gpg", false, "print OpenPGP armored keys instead of PEM")
+	rsaKey.Flags().StringVar(&rsaName, "name", "", "name of the gpg user id")
+	rsaKey.Flags().StringVar(&rsaComment, "comment", "", "comment of the gpg user id")
+	rsaKey.Flags().StringVar(&rsaEmail, "email", "", "email of the gpg user id")
+
+	addKeyFlags(sshKey)
+	sshKey.Flags().StringVarP(&sshComment, "comment", "c", "", "comment of the ssh key, e.g. user@host")
+
+	addKeyFlags(ageKey)
+
+	rootCmd.AddCommand(rsaKey)
+	rootCmd.AddCommand(sshKey)
+	rootCmd.AddCommand(ageKey)
+}
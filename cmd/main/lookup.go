@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"sync"
+)
+
+var (
+	lookupChild       string
+	lookupFingerprint string
+	lookupChildPass   string
+	lookupStart       uint32
+	lookupCount       uint32
+	lookupWordCounts  []int
+	lookupLanguages   []string
+	lookupSkipLegacy  bool
+	lookupWorkers     int
+)
+
+var lookup = &cobra.Command{
+	Use:   "lookup -m mnemonic [--child | -c] [--fingerprint | -f] [--start] [--count | -n] [--words | -l] [--language | -g]",
+	Short: "find the index which derives a child mnemonic",
+	Long: "find the index which derives a child mnemonic from master mnemonic, indices are searched by the BIP39 " +
+		"application of bip-85 and by the legacy scheme of derive command. the child is given as mnemonic, " +
+		"or as its master fingerprint which needs all word counts and languages to be checked",
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := mderive.LookupOptions{
+			Mnemonic:   lookupChild,
+			Passphrase: lookupChildPass,
+			Start:      lookupStart,
+			Count:      lookupCount,
+			WordCounts: lookupWordCounts,
+			SkipLegacy: lookupSkipLegacy,
+			Workers:    lookupWorkers,
+		}
+
+		if lookupFingerprint != "" {
+			fingerprint, err := hex.DecodeString(lookupFingerprint)
+			if err != nil || len(fingerprint) != 4 {
+				fmt.Println("fingerprint should be 4 bytes in hex")
+				return
+			}
+			opts.Fingerprint = fingerprint
+		}
+
+		for _, name := range lookupLanguages {
+			lang, err := mderive.ParseLanguage(name)
+			if err != nil {
+				fmt.Printf("parse mnemonic language failed: %s\n", err)
+				return
+			}
+			opts.Languages = append(opts.Languages, lang)
+		}
+
+		master, err := newMasterKey(keyMnemonic, keyPassphrase)
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		var mutex sync.Mutex
+		lastPercent := -1
+		opts.Progress = func(checked, total uint64) {
+			percent := int(checked * 100 / total)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if percent > lastPercent {
+				lastPercent = percent
+				fmt.Fprintf(os.Stderr, "\rchecked %d/%d candidates (%d%%)", checked, total, percent)
+			}
+		}
+
+		result, err := mderive.LookupIndex(master, opts)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			fmt.Printf("lookup index failed: %s\n", err)
+			printWordSuggestions(err)
+			return
+		}
+
+		scheme := "bip-85"
+		if result.Legacy {
+			scheme = "legacy"
+		}
+
+		fmt.Printf("found %d words %s mnemonic at index %d by %s path [%s]\n",
+			result.Words, result.Language, result.Index, scheme, result.Path)
+	},
+}
+
+func init() {
+	lookup.Flags().StringVarP(&keyMnemonic, "mnemonic", "m", "", "master mnemonic")
+	lookup.Flags().StringVarP(&keyPassphrase, "passphrase", "e", "", "master mnemonic passphrase")
+	lookup.Flags().StringVarP(&lookupChild, "child", "c", "", "child mnemonic to look up")
+	lookup.Flags().StringVarP(&lookupFingerprint, "fingerprint", "f", "", "master fingerprint of child mnemonic in hex")
+	lookup.Flags().StringVar(&lookupChildPass, "child-passphrase", "", "passphrase of child mnemonic used with --fingerprint")
+	lookup.Flags().Uint32Var(&lookupStart, "start", 0, "first index to search")
+	lookup.Flags().Uint32VarP(&lookupCount, "count", "n", 1000, "count of indices to search")
+	lookup.Flags().IntSliceVarP(&lookupWordCounts, "words", "l", nil, "word counts to search, default is all")
+	lookup.Flags().StringSliceVarP(&lookupLanguages, "language", "g", nil, "languages to search, default is all")
+	lookup.Flags().BoolVar(&lookupSkipLegacy, "skip-legacy", false, "skip the legacy scheme of derive command")
+	lookup.Flags().IntVarP(&lookupWorkers, "workers", "w", 0, "parallel workers, default is the count of CPU cores")
+
+	rootCmd.AddCommand(lookup)
+}
@@ -1,11 +1,59 @@
 package main
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	wordlistFiles     []string
+	wordlistUnchecked bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "mderive",
 	Short: "M-Derive is a derivation tool to manage multiple mnemonic.",
 	Long:  "M-Derive is a derivation tool to manage multiple mnemonic.",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return loadWordlists(wordlistFiles)
+	},
+}
+
+// loadWordlists registers wordlist files given as "name=path" or "path", the
+// language name defaults to the file name without extension, e.g. portuguese.txt.
+// words must be sorted and unique by their first 4 letters unless --wordlist-unchecked is set.
+func loadWordlists(files []string) error {
+	checks := mderive.WordlistChecks{Sorted: true, MaxPrefixLength: 4}
+	if wordlistUnchecked {
+		checks = mderive.WordlistChecks{}
+	}
+
+	for _, file := range files {
+		name, path, found := strings.Cut(file, "=")
+		if !found {
+			path = file
+			name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+
+		lang, err := mderive.LoadWordlist(name, path, checks)
+		if err != nil {
+			return fmt.Errorf("load wordlist %s: %w", path, err)
+		}
+
+		wl, _ := lang.Wordlist()
+		fmt.Fprintf(os.Stderr, "loaded wordlist %s, checksum %s\n", lang, wl.Checksum())
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayVar(&wordlistFiles, "wordlist", nil, "load a custom wordlist file with one word per line as name=path or path")
+	rootCmd.PersistentFlags().BoolVar(&wordlistUnchecked, "wordlist-unchecked", false, "skip the sorting and unique 4 letters prefix checks of custom wordlists")
 }
 
 func main() {
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"path/filepath"
+)
+
+var (
+	passwordSite     string
+	passwordUsername string
+	passwordCounter  uint32
+	passwordLength   int
+	passwordClasses  string
+	passwordBase64   bool
+	passwordSave     bool
+	passwordList     bool
+	passwordRegistry string
+)
+
+var password = &cobra.Command{
+	Use:   "password -m mnemonic --site site [--user | -u] [--counter | -c] [--length | -l] [--classes] [--base64] [--save] [--list]",
+	Short: "derive site password by master mnemonic",
+	Long: "derive site password by the PWD BASE85 or PWD BASE64 application of bip-85, the index is derived from " +
+		"site, username and counter. labels saved by --save are found in the registry by site and username, " +
+		"so the length, classes and counter need not be given again",
+	Run: func(cmd *cobra.Command, args []string) {
+		registryPath := passwordRegistry
+		if registryPath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				fmt.Printf("find home directory failed: %s\n", err)
+				return
+			}
+			registryPath = filepath.Join(home, ".mderive", "passwords.json")
+		}
+
+		registry, err := mderive.LoadPasswordRegistry(registryPath)
+		if err != nil {
+			fmt.Printf("load password registry failed: %s\n", err)
+			return
+		}
+
+		if passwordList {
+			for _, label := range registry.Labels {
+				fmt.Printf("%s\tuser=%s\tcounter=%d\tlength=%d\tclasses=%s\t%s\n",
+					label.Site, label.Username, label.Counter, label.Length, label.Classes, label.Encoding)
+			}
+			return
+		}
+
+		label, found := registry.Find(passwordSite, passwordUsername)
+		if !found {
+			label = mderive.PasswordLabel{Site: passwordSite, Username: passwordUsername}
+		}
+
+		if err := applyPasswordFlags(cmd, &label); err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		master, err := newMasterKey(keyMnemonic, keyPassphrase)
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		result, err := mderive.DeriveSitePassword(master, label)
+		if err != nil {
+			fmt.Printf("derive site password failed: %s\n", err)
+			return
+		}
+
+		if passwordSave {
+			registry.Put(label)
+			if err := registry.Save(registryPath); err != nil {
+				fmt.Printf("save password registry failed: %s\n", err)
+				return
+			}
+		}
+
+		fmt.Println(result)
+	},
+}
+
+// applyPasswordFlags overrides label by the flags given in command line
+func applyPasswordFlags(cmd *cobra.Command, label *mderive.PasswordLabel) error {
+	flags := cmd.Flags()
+	if flags.Changed("counter") {
+		label.Counter = passwordCounter
+	}
+
+	if flags.Changed("length") {
+		label.Length = passwordLength
+	}
+
+	if flags.Changed("classes") {
+		classes, err := mderive.ParseCharClass(passwordClasses)
+		if err != nil {
+			return err
+		}
+		label.Classes = classes
+	}
+
+	if flags.Changed("base64") {
+		label.Encoding = mderive.PasswordBase85
+		if passwordBase64 {
+			label.Encoding = mderive.PasswordBase64
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	password.Flags().StringVarP(&keyMnemonic, "mnemonic", "m", "", "master mnemonic")
+	password.Flags().StringVarP(&keyPassphrase, "passphrase", "e", "", "master mnemonic passphrase")
+	password.Flags().StringVarP(&passwordSite, "site", "s", "", "site name, e.g. example.com")
+	password.Flags().StringVarP(&passwordUsername, "user", "u", "", "username of the site")
+	password.Flags().Uint32VarP(&passwordCounter, "counter", "c", 0, "increase to rotate the password")
+	password.Flags().IntVarP(&passwordLength, "length", "l", 20, "password length")
+	password.Flags().StringVar(&passwordClasses, "classes", "luds", "allowed character classes, l lowercase, u uppercase, d digits, s symbols")
+	password.Flags().BoolVar(&passwordBase64, "base64", false, "use PWD BASE64 application instead of PWD BASE85")
+	password.Flags().BoolVar(&passwordSave, "save", false, "save the label to password registry")
+	password.Flags().BoolVar(&passwordList, "list", false, "list labels in password registry")
+	password.Flags().StringVar(&passwordRegistry, "registry", "", "password registry file, default is ~/.mderive/passwords.json")
+
+	rootCmd.AddCommand(password)
+}
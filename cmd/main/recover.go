@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	recoverMnemonic     string
+	recoverLanguage     string
+	recoverPassphrase   string
+	recoverExtendedKey  string
+	recoverPath         string
+	recoverFingerprint  string
+	recoverUnknownOrder bool
+	recoverWorkers      int
+	recoverLimit        int
+)
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover -m mnemonic [--unordered] [--xpub | -x] [--path | -p] [--fingerprint | -f] [--passphrase | -e]",
+	Short: "recover mnemonic with missing or misplaced words",
+	Long: "recover mnemonic with missing or misplaced words, mark each missing word as \"?\". " +
+		"candidates are filtered by checksum and confirmed by extended key or master fingerprint if given",
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := mderive.RecoverOptions{
+			UnknownOrder: recoverUnknownOrder,
+			Passphrase:   recoverPassphrase,
+			Path:         recoverPath,
+			Workers:      recoverWorkers,
+			Limit:        recoverLimit,
+		}
+
+		if recoverExtendedKey != "" {
+			key, err := mderive.Base58Decode(recoverExtendedKey)
+			if err != nil {
+				fmt.Printf("decode extended key failed: %s\n", err)
+				return
+			}
+			opts.ExtendedKey = key
+		}
+
+		if recoverFingerprint != "" {
+			fingerprint, err := hex.DecodeString(recoverFingerprint)
+			if err != nil || len(fingerprint) != 4 {
+				fmt.Println("fingerprint should be 4 bytes in hex")
+				return
+			}
+			opts.Fingerprint = fingerprint
+		}
+
+		var langs []mderive.Language
+		if recoverLanguage != "" {
+			lang, err := mderive.ParseLanguage(recoverLanguage)
+			if err != nil {
+				fmt.Printf("parse mnemonic language failed: %s\n", err)
+				return
+			}
+			langs = append(langs, lang)
+		}
+
+		var mutex sync.Mutex
+		lastPercent := -1
+		opts.Progress = func(checked, total uint64) {
+			percent := int(checked * 100 / total)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if percent > lastPercent {
+				lastPercent = percent
+				fmt.Fprintf(os.Stderr, "\rchecked %d/%d candidates (%d%%)", checked, total, percent)
+			}
+		}
+
+		results, err := mderive.RecoverMnemonic(strings.Fields(recoverMnemonic), opts, langs...)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			fmt.Printf("recover mnemonic failed: %s\n", err)
+			printWordSuggestions(err)
+			return
+		}
+
+		fmt.Printf("recover %d mnemonics:\n", len(results))
+		for _, result := range results {
+			fmt.Println(result)
+		}
+	},
+}
+
+func init() {
+	recoverCmd.Flags().StringVarP(&recoverMnemonic, "mnemonic", "m", "", "damaged mnemonic, use \"?\" for missing words")
+	recoverCmd.Flags().StringVarP(&recoverLanguage, "language", "g", "", "mnemonic language, detected by known words if empty")
+	recoverCmd.Flags().StringVarP(&recoverPassphrase, "passphrase", "e", "", "mnemonic passphrase used to confirm candidates")
+	recoverCmd.Flags().StringVarP(&recoverExtendedKey, "xpub", "x", "", "known extended key derived by path to confirm candidates")
+	recoverCmd.Flags().StringVarP(&recoverPath, "path", "p", "m", "derive path of the known extended key")
+	recoverCmd.Flags().StringVarP(&recoverFingerprint, "fingerprint", "f", "", "known master key fingerprint in hex to confirm candidates")
+	recoverCmd.Flags().BoolVar(&recoverUnknownOrder, "unordered", false, "words are in unknown order")
+	recoverCmd.Flags().IntVarP(&recoverWorkers, "workers", "w", 0, "parallel workers, default is the count of CPU cores")
+	recoverCmd.Flags().IntVarP(&recoverLimit, "limit", "n", 0, "stop after found count of mnemonics, 0 means no limit")
+
+	rootCmd.AddCommand(recoverCmd)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"github.io/decision2016/go-derived-mnemonic/slip39"
+)
+
+var (
+	splitMnemonic          string
+	splitPassphrase        string
+	splitGroupThreshold    int
+	splitGroups            []string
+	splitIterationExponent int
+	splitExtendable        bool
+
+	combineShares     []string
+	combinePassphrase string
+	combineLanguage   string
+)
+
+var split = &cobra.Command{
+	Use:   "split -m mnemonic [--threshold | -t] [--group | -G] [--passphrase | -e]",
+	Short: "split mnemonic into slip-39 shares",
+	Long:  "split mnemonic into slip-39 shares, each group is given as \"threshold/count\", e.g. -G 2/3 -G 3/5",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := slip39.Config{
+			GroupThreshold:    splitGroupThreshold,
+			IterationExponent: splitIterationExponent,
+			Extendable:        splitExtendable,
+		}
+
+		for _, group := range splitGroups {
+			var threshold, count int
+			if _, err := fmt.Sscanf(group, "%d/%d", &threshold, &count); err != nil {
+				fmt.Printf("parse group %s failed, it should be threshold/count\n", group)
+				return
+			}
+
+			config.Groups = append(config.Groups, slip39.Group{MemberThreshold: threshold, MemberCount: count})
+		}
+
+		groups, err := slip39.SplitMnemonic(splitMnemonic, splitPassphrase, config)
+		if err != nil {
+			fmt.Printf("split mnemonic failed: %s\n", err)
+			printWordSuggestions(err)
+			return
+		}
+
+		fmt.Printf("split mnemonic into %d groups, %d groups are required to recover:\n", len(groups), splitGroupThreshold)
+		for idx, shares := range groups {
+			fmt.Printf("group %d, %d of %d shares are required:\n", idx+1, config.Groups[idx].MemberThreshold, len(shares))
+			for _, share := range shares {
+				fmt.Println(share)
+			}
+		}
+	},
+}
+
+var combine = &cobra.Command{
+	Use:   "combine -s share [-s share ...] [--passphrase | -e] [--language | -g]",
+	Short: "combine slip-39 shares into mnemonic",
+	Long:  "combine slip-39 shares into mnemonic",
+	Run: func(cmd *cobra.Command, args []string) {
+		lang, err := mderive.ParseLanguage(combineLanguage)
+		if err != nil {
+			fmt.Printf("parse mnemonic language failed: %s\n", err)
+			return
+		}
+
+		mnemonic, err := slip39.CombineMnemonic(combineShares, combinePassphrase, lang)
+		if err != nil {
+			fmt.Printf("combine shares failed: %s\n", err)
+			return
+		}
+
+		fmt.Println("combine mnemonic:")
+		fmt.Println(mnemonic)
+	},
+}
+
+func init() {
+	split.Flags().StringVarP(&splitMnemonic, "mnemonic", "m", "", "mnemonic need to be split")
+	split.Flags().StringVarP(&splitPassphrase, "passphrase", "e", "", "passphrase to encrypt the master secret")
+	split.Flags().IntVarP(&splitGroupThreshold, "threshold", "t", 1, "count of groups required to recover")
+	split.Flags().StringArrayVarP(&splitGroups, "group", "G", []string{"2/3"}, "member threshold and count of a group, e.g. 2/3")
+	split.Flags().IntVar(&splitIterationExponent, "exponent", 1, "iteration exponent of the passphrase encryption")
+	split.Flags().BoolVar(&splitExtendable, "extendable", true, "create extendable shares")
+
+	combine.Flags().StringArrayVarP(&combineShares, "share", "s", nil, "slip-39 share, repeat for each share")
+	combine.Flags().StringVarP(&combinePassphrase, "passphrase", "e", "", "passphrase to decrypt the master secret")
+	combine.Flags().StringVarP(&combineLanguage, "language", "g", "english", "language of the combined mnemonic")
+
+	rootCmd.AddCommand(split)
+	rootCmd.AddCommand(combine)
+}
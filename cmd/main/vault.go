@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"path/filepath"
+)
+
+var (
+	vaultFile     string
+	vaultLabel    string
+	vaultLength   int
+	vaultLanguage string
+	vaultIndex    uint32
+)
+
+var vault = &cobra.Command{
+	Use:   "vault",
+	Short: "manage labelled child mnemonics in encrypted vault",
+	Long: "vault maps labels to the bip-85 paths of child mnemonics, so indices are never lost. " +
+		"the vault file is encrypted by a key derived from master mnemonic at m/83696968'/867684'/0'",
+}
+
+var vaultAdd = &cobra.Command{
+	Use:   "add -m mnemonic --label label [--length | -l] [--language | -g] [--index | -i]",
+	Short: "add a labelled child mnemonic to vault",
+	Long:  "add a child mnemonic derived by the BIP39 application of bip-85, index defaults to the first unused index",
+	Run: func(cmd *cobra.Command, args []string) {
+		master, v, path, err := openVault()
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		lang, err := mderive.ParseLanguage(vaultLanguage)
+		if err != nil {
+			fmt.Printf("parse mnemonic language failed: %s\n", err)
+			return
+		}
+
+		index := vaultIndex
+		if !cmd.Flags().Changed("index") {
+			index = v.NextIndex(lang, vaultLength)
+		}
+
+		entry, err := mderive.NewVaultEntry(vaultLabel, lang, vaultLength, index)
+		if err != nil {
+			fmt.Printf("create vault entry failed: %s\n", err)
+			return
+		}
+
+		mnemonic, err := entry.Derive(master)
+		if err != nil {
+			fmt.Printf("derive mnemonic failed: %s\n", err)
+			return
+		}
+
+		if err := v.Add(entry); err != nil {
+			fmt.Printf("add vault entry failed: %s\n", err)
+			return
+		}
+
+		if err := v.Save(master, path); err != nil {
+			fmt.Printf("save vault failed: %s\n", err)
+			return
+		}
+
+		fmt.Printf("added %s at path [%s]:\n", entry.Label, entry.Path)
+		fmt.Println(mnemonic)
+	},
+}
+
+var vaultList = &cobra.Command{
+	Use:   "list -m mnemonic",
+	Short: "list labels in vault",
+	Run: func(cmd *cobra.Command, args []string) {
+		_, v, _, err := openVault()
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		for _, entry := range v.Entries {
+			fmt.Printf("%s\t[%s]\t%d words\t%s\t%s\n", entry.Label, entry.Path, entry.Words,
+				entry.Language, entry.Created.Format("2006-01-02"))
+		}
+	},
+}
+
+var vaultDerive = &cobra.Command{
+	Use:   "derive -m mnemonic --label label",
+	Short: "derive the child mnemonic of a label in vault",
+	Run: func(cmd *cobra.Command, args []string) {
+		master, v, _, err := openVault()
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		entry, found := v.Find(vaultLabel)
+		if !found {
+			fmt.Printf("label %s not found in vault\n", vaultLabel)
+			return
+		}
+
+		mnemonic, err := entry.Derive(master)
+		if err != nil {
+			fmt.Printf("derive mnemonic failed: %s\n", err)
+			return
+		}
+
+		fmt.Println(mnemonic)
+	},
+}
+
+var vaultRemove = &cobra.Command{
+	Use:   "remove -m mnemonic --label label",
+	Short: "remove a label from vault",
+	Run: func(cmd *cobra.Command, args []string) {
+		master, v, path, err := openVault()
+		if err != nil {
+			fmt.Println(err)
+			printWordSuggestions(err)
+			return
+		}
+
+		if !v.Remove(vaultLabel) {
+			fmt.Printf("label %s not found in vault\n", vaultLabel)
+			return
+		}
+
+		if err := v.Save(master, path); err != nil {
+			fmt.Printf("save vault failed: %s\n", err)
+			return
+		}
+	},
+}
+
+// openVault decrypts the vault file by master mnemonic
+func openVault() (*mderive.Key, *mderive.Vault, string, error) {
+	path := vaultFile
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("find home directory failed: %w", err)
+		}
+		path = filepath.Join(home, ".mderive", "vault.json")
+	}
+
+	master, err := newMasterKey(keyMnemonic, keyPassphrase)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	v, err := mderive.OpenVault(master, path)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("open vault failed: %w", err)
+	}
+
+	return master, v, path, nil
+}
+
+func init() {
+	vault.PersistentFlags().StringVarP(&keyMnemonic, "mnemonic", "m", "", "master mnemonic")
+	vault.PersistentFlags().StringVarP(&keyPassphrase, "passphrase", "e", "", "master mnemonic passphrase")
+	vault.PersistentFlags().StringVar(&vaultFile, "file", "", "vault file, default is ~/.mderive/vault.json")
+
+	for _, cmd := range []*cobra.Command{vaultAdd, vaultDerive, vaultRemove} {
+		cmd.Flags().StringVar(&vaultLabel, "label", "", "label of child mnemonic")
+	}
+
+	vaultAdd.Flags().IntVarP(&vaultLength, "length", "l", 12, "mnemonic length, must be 12, 15, 18, 21 or 24")
+	vaultAdd.Flags().StringVarP(&vaultLanguage, "language", "g", "english", "mnemonic language")
+	vaultAdd.Flags().Uint32VarP(&vaultIndex, "index", "i", 0, "bip-85 index, default is the first unused index")
+
+	vault.AddCommand(vaultAdd, vaultList, vaultDerive, vaultRemove)
+	rootCmd.AddCommand(vault)
+}
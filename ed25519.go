@@ -0,0 +1,69 @@
+package mderive
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/pem"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ssh"
+	"strings"
+)
+
+// AppEd25519 is a private application number of Ed25519 keys, ascii of "ED"
+const AppEd25519 = 6968
+
+// DeriveEd25519 derives an Ed25519 key whose seed is the first 32 bytes of
+// entropy at m/83696968'/6968'/{index}'.
+func DeriveEd25519(key *Key, index uint32) (ed25519.PrivateKey, error) {
+	entropy, err := deriveAppEntropy(key, AppEd25519, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return ed25519.NewKeyFromSeed(entropy[:ed25519.SeedSize]), nil
+}
+
+// MarshalOpenSSHPrivateKey encodes priv as an OpenSSH private key, which can be
+// saved as ~/.ssh/id_ed25519.
+func MarshalOpenSSHPrivateKey(priv ed25519.PrivateKey, comment string) ([]byte, error) {
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// MarshalOpenSSHPublicKey encodes the public key of priv in authorized_keys
+// format, e.g. "ssh-ed25519 AAAA... comment".
+func MarshalOpenSSHPublicKey(priv ed25519.PrivateKey, comment string) (string, error) {
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n")
+	if comment != "" {
+		line += " " + comment
+	}
+
+	return line, nil
+}
+
+// AgeKeys converts priv into an age X25519 identity "AGE-SECRET-KEY-1..." and
+// its recipient "age1...", the conversion is the same as ssh-to-age so the
+// recipient can also be computed from the ssh public key.
+func AgeKeys(priv ed25519.PrivateKey) (identity string, recipient string, err error) {
+	digest := sha512.Sum512(priv.Seed())
+	scalar := digest[:curve25519.ScalarSize]
+
+	public, err := curve25519.X25519(scalar, curve25519.Basepoint)
+	if err != nil {
+		return "", "", err
+	}
+
+	identity = strings.ToUpper(bech32Encode("age-secret-key-", scalar))
+	recipient = bech32Encode("age", public)
+
+	return identity, recipient, nil
+}
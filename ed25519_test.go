@@ -0,0 +1,98 @@
+package mderive
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"golang.org/x/crypto/ssh"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDeriveEd25519(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	priv, err := DeriveEd25519(key, 0)
+	if err != nil {
+		t.Fatalf("derive ed25519 key failed: %s", err)
+	}
+
+	entropy, _ := DeriveEntropyForMnemonic(key, "m/83696968'/6968'/0'")
+	if hex.EncodeToString(priv.Seed()) != hex.EncodeToString(entropy[:32]) {
+		t.Fatalf("derive ed25519 key with wrong seed")
+	}
+
+	pemBytes, err := MarshalOpenSSHPrivateKey(priv, "alice@host")
+	if err != nil {
+		t.Fatalf("marshal openssh private key failed: %s", err)
+	}
+
+	parsed, err := ssh.ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		t.Fatalf("parse openssh private key failed: %s", err)
+	}
+
+	if !priv.Equal(*parsed.(*ed25519.PrivateKey)) {
+		t.Fatalf("parse wrong openssh private key")
+	}
+
+	line, err := MarshalOpenSSHPublicKey(priv, "alice@host")
+	if err != nil {
+		t.Fatalf("marshal openssh public key failed: %s", err)
+	}
+
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil || comment != "alice@host" || pub.Type() != ssh.KeyAlgoED25519 {
+		t.Fatalf("marshal wrong openssh public key: %s", line)
+	}
+}
+
+func TestAgeKeys(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+	priv, _ := DeriveEd25519(key, 0)
+
+	identity, recipient, err := AgeKeys(priv)
+	if err != nil {
+		t.Fatalf("convert age keys failed: %s", err)
+	}
+
+	if !strings.HasPrefix(identity, "AGE-SECRET-KEY-1") || len(identity) != 74 {
+		t.Fatalf("convert wrong age identity: %s", identity)
+	}
+
+	// the recipient is the montgomery form u = (1 + y) / (1 - y) of ed25519 public key
+	pub := []byte(priv.Public().(ed25519.PublicKey))
+	reversed := make([]byte, len(pub))
+	for i := range pub {
+		reversed[len(pub)-1-i] = pub[i]
+	}
+	reversed[0] &= 0x7f
+
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	y := new(big.Int).SetBytes(reversed)
+	numerator := new(big.Int).Add(big.NewInt(1), y)
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, p).ModInverse(denominator, p)
+	u := numerator.Mul(numerator, denominator).Mod(numerator, p)
+
+	uBytes := make([]byte, 32)
+	u.FillBytes(uBytes)
+	for i, j := 0, len(uBytes)-1; i < j; i, j = i+1, j-1 {
+		uBytes[i], uBytes[j] = uBytes[j], uBytes[i]
+	}
+
+	if recipient != bech32Encode("age", uBytes) {
+		t.Fatalf("convert wrong age recipient: %s", recipient)
+	}
+}
+
+func TestBech32Encode(t *testing.T) {
+	if encoded := bech32Encode("A", nil); encoded != "a12uel5l" {
+		t.Fatalf("bech32 encode wrong string: %s", encoded)
+	}
+
+	data, _ := hex.DecodeString("00443214c74254b635cf84653a56d7c675be77df")
+	if encoded := bech32Encode("abcdef", data); encoded != "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw" {
+		t.Fatalf("bech32 encode wrong string: %s", encoded)
+	}
+}
@@ -9,10 +9,86 @@ var (
 	ErrInvalidPublicKey       = fmt.Errorf("invalid public key")
 	ErrSerializedKeyWrongSize = fmt.Errorf("serialized keys should by exactly 82 bytes")
 	ErrInvalidChecksum        = fmt.Errorf("checksum doesn't match")
+	ErrKeyVersionUnknown      = fmt.Errorf("extended key version is unknown")
+	ErrKeyVersionMismatch     = fmt.Errorf("extended key version doesn't match the key type")
+	ErrPrivateKeyPrefix       = fmt.Errorf("private key should be prefixed by 0x00")
+	ErrPublicKeyPrefix        = fmt.Errorf("public key should be prefixed by 0x02 or 0x03")
+	ErrPrivateKeyOutOfRange   = fmt.Errorf("private key should be in range [1, n-1]")
+	ErrZeroDepthFingerprint   = fmt.Errorf("master key should have zero parent fingerprint")
+	ErrZeroDepthChildNumber   = fmt.Errorf("master key should have zero child number")
+	ErrKeyFieldSize           = fmt.Errorf("extended key field has wrong size")
+	ErrInvalidBase58          = fmt.Errorf("invalid base58 string")
+	ErrInvalidChildKey        = fmt.Errorf("child key is invalid, proceed with the next index")
+	ErrChildIndexExhausted    = fmt.Errorf("no valid child key left in the index range")
 
 	ErrEntropyBitsLengthInvalid = fmt.Errorf("entropy bits length should in range [128, 256] and as a multiple of 32")
 	ErrMnemonicLengthInvalid    = fmt.Errorf("mnemonic output length must be 12, 15, 18, 21 or 24")
+	ErrMnemonicEmpty            = fmt.Errorf("mnemonic has no words")
 	ErrEntropyChecksumError     = fmt.Errorf("entropy checksum is wrong")
+	ErrLanguageUnsupported      = fmt.Errorf("mnemonic language is not supported")
+	ErrLanguageUndetected       = fmt.Errorf("mnemonic language can't be detected")
+	ErrLanguageAmbiguous        = fmt.Errorf("mnemonic is valid in multiple languages with different entropy")
+	ErrLanguageExists           = fmt.Errorf("language with the same name is already registered")
+	ErrLanguageNameInvalid      = fmt.Errorf("language name should not be empty")
+	ErrRecoverSpaceTooLarge     = fmt.Errorf("too many unknown words to recover mnemonic")
+
+	ErrEntropyInsufficient = fmt.Errorf("not enough rolls, flips or cards to produce entropy")
+	ErrDiceSidesInvalid    = fmt.Errorf("dice should have at least 2 sides")
+	ErrDiceRollInvalid     = fmt.Errorf("dice roll should in range [1, sides]")
+	ErrCardInvalid         = fmt.Errorf("card should be rank and suit, e.g. AS, 10H, QD or KC")
+	ErrCardDuplicated      = fmt.Errorf("card is duplicated in deck")
+	ErrEntropyHealthTest   = fmt.Errorf("entropy source failed health test")
 
 	ErrDerivationPathInvalid = fmt.Errorf("derivation path invalid")
+	ErrPathSegmentEmpty      = fmt.Errorf("%w: empty segment", ErrDerivationPathInvalid)
+	ErrPathIndexInvalid      = fmt.Errorf("%w: index should be digits with optional ', h or H", ErrDerivationPathInvalid)
+	ErrPathIndexOutOfRange   = fmt.Errorf("%w: index should be less than 2^31", ErrDerivationPathInvalid)
+	ErrPathNoParent          = fmt.Errorf("derivation path has no parent")
+	ErrBIP85ParamInvalid     = fmt.Errorf("bip-85 application parameter out of range")
+	ErrDRNGEntropyInvalid    = fmt.Errorf("bip85-drng should be seeded by 64 bytes entropy")
+	ErrLookupNotFound        = fmt.Errorf("no index in range derives the child mnemonic")
+	ErrLookupTargetInvalid   = fmt.Errorf("child mnemonic or its 4 bytes fingerprint should be given")
+	ErrPasswordLabelInvalid  = fmt.Errorf("password label should have a site and allow enough characters")
+	ErrPasswordTooLong       = fmt.Errorf("password is too long to be filled by the allowed character classes")
+	ErrGPGUserIdInvalid      = fmt.Errorf("gpg user id should not contain any of \"()<>\\x00\"")
+	ErrVaultLabelInvalid     = fmt.Errorf("vault label should not be empty")
+	ErrVaultLabelExists      = fmt.Errorf("vault label already exists")
+	ErrVaultIndexUsed        = fmt.Errorf("vault index is already used by another label")
+	ErrVaultKeyMismatch      = fmt.Errorf("vault is encrypted by another master mnemonic")
+	ErrVaultCorrupted        = fmt.Errorf("vault file is corrupted or modified")
+	ErrVaultVersionInvalid   = fmt.Errorf("vault file version is not supported")
+	ErrExportPassphraseEmpty = fmt.Errorf("export passphrase should not be empty")
+	ErrExportPassphraseWrong = fmt.Errorf("export passphrase is wrong or the text is modified")
+	ErrExportFormatInvalid   = fmt.Errorf("exported mnemonic text invalid")
+	ErrExportVersionInvalid  = fmt.Errorf("exported mnemonic version is not supported")
+	ErrExportParamInvalid    = fmt.Errorf("argon2id parameter of export out of range")
 )
+
+// WordNotFoundError reports a mnemonic word which doesn't exist in wordlist,
+// Suggestions contains the closest words of wordlist.
+type WordNotFoundError struct {
+	Word        string
+	Position    int
+	Language    Language
+	Suggestions []string
+}
+
+func (e *WordNotFoundError) Error() string {
+	return fmt.Sprintf("word %s not exists in wordlist", e.Word)
+}
+
+// PathError reports the segment of derivation path which can't be parsed or
+// derived, Segment is counted from 0 after "m".
+type PathError struct {
+	Segment int
+	Value   string
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("derivation path segment %d (%q): %s", e.Segment, e.Value, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,182 @@
+package mderive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+const (
+	// ExportPrefix starts the text of exported mnemonics, it is followed by the
+	// base58 payload with a 4 bytes checksum as extended keys.
+	ExportPrefix = "mderive-export:"
+
+	exportVersion = 1
+
+	exportSaltSize = 16
+
+	// header is version, argon2id time, memory in KiB and threads
+	exportHeaderSize = 1 + 1 + 4 + 1
+
+	// the header isn't authenticated before argon2id runs, so imports asking
+	// more than 1 GiB memory or 16 passes are rejected instead of stalling
+	maxExportMemory = 1 << 20
+	maxExportTime   = 16
+)
+
+// ExportParams are the Argon2id parameters of passphrase, they are saved in the
+// exported text so they can be increased later, up to 16 passes and 1 GiB.
+type ExportParams struct {
+	Time    uint8
+	Memory  uint32 // KiB
+	Threads uint8
+}
+
+// DefaultExportParams follows the second recommended option of RFC 9106.
+var DefaultExportParams = ExportParams{Time: 3, Memory: 64 << 10, Threads: 4}
+
+// ExportMnemonic encrypts the entropy of mnemonic by passphrase, the key is
+// stretched by Argon2id and the entropy is sealed by XChaCha20-Poly1305. The
+// language of mnemonic is encrypted with entropy, so ImportMnemonic restores
+// the same words.
+func ExportMnemonic(mnemonic string, passphrase string, lang ...Language) (string, error) {
+	return exportMnemonic(mnemonic, passphrase, DefaultExportParams, lang)
+}
+
+func exportMnemonic(mnemonic string, passphrase string, params ExportParams, lang []Language) (string, error) {
+	if passphrase == "" {
+		return "", ErrExportPassphraseEmpty
+	}
+
+	if err := params.validate(); err != nil {
+		return "", err
+	}
+
+	entropy, wl, err := decodeMnemonic(strings.Fields(mnemonic), lang)
+	if err != nil {
+		return "", err
+	}
+
+	if len(wl.name) > 255 {
+		return "", ErrLanguageNameInvalid
+	}
+
+	header := make([]byte, exportHeaderSize)
+	header[0] = exportVersion
+	header[1] = params.Time
+	binary.BigEndian.PutUint32(header[2:6], params.Memory)
+	header[6] = params.Threads
+
+	salt := make([]byte, exportSaltSize)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// plaintext is len(name) || name || entropy
+	plaintext := []byte{byte(len(wl.name))}
+	plaintext = append(plaintext, wl.name...)
+	plaintext = append(plaintext, entropy...)
+
+	aead, err := chacha20poly1305.NewX(params.key(passphrase, salt))
+	if err != nil {
+		return "", err
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.Write(header)
+	buffer.Write(salt)
+	buffer.Write(nonce)
+	buffer.Write(aead.Seal(nil, nonce, plaintext, buffer.Bytes()))
+
+	payload, err := addChecksumToBytes(buffer.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	return ExportPrefix + b58Encode(payload), nil
+}
+
+// ImportMnemonic decrypts the text of ExportMnemonic by passphrase, the
+// entropy is validated again by NewMnemonicByEntropy.
+func ImportMnemonic(text string, passphrase string) (string, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, ExportPrefix) {
+		return "", ErrExportFormatInvalid
+	}
+
+	payload, err := b58Decode(strings.TrimPrefix(text, ExportPrefix))
+	if err != nil || len(payload) < exportHeaderSize+exportSaltSize+chacha20poly1305.NonceSizeX+4 {
+		return "", ErrExportFormatInvalid
+	}
+
+	data, cs := payload[:len(payload)-4], payload[len(payload)-4:]
+	expect, err := checksum(data)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(cs, expect) {
+		return "", ErrInvalidChecksum
+	}
+
+	if data[0] != exportVersion {
+		return "", ErrExportVersionInvalid
+	}
+
+	params := ExportParams{
+		Time:    data[1],
+		Memory:  binary.BigEndian.Uint32(data[2:6]),
+		Threads: data[6],
+	}
+	if err := params.validate(); err != nil {
+		return "", err
+	}
+
+	salt := data[exportHeaderSize : exportHeaderSize+exportSaltSize]
+	nonceEnd := exportHeaderSize + exportSaltSize + chacha20poly1305.NonceSizeX
+	nonce := data[exportHeaderSize+exportSaltSize : nonceEnd]
+
+	aead, err := chacha20poly1305.NewX(params.key(passphrase, salt))
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, data[nonceEnd:], data[:nonceEnd])
+	if err != nil {
+		return "", ErrExportPassphraseWrong
+	}
+
+	if len(plaintext) < 1 || len(plaintext) < 1+int(plaintext[0]) {
+		return "", ErrExportFormatInvalid
+	}
+
+	nameEnd := 1 + int(plaintext[0])
+	lang, err := ParseLanguage(string(plaintext[1:nameEnd]))
+	if err != nil {
+		return "", err
+	}
+
+	return NewMnemonicByEntropy(plaintext[nameEnd:], lang)
+}
+
+func (params ExportParams) validate() error {
+	if params.Time == 0 || params.Time > maxExportTime || params.Threads == 0 ||
+		params.Memory < 8*uint32(params.Threads) || params.Memory > maxExportMemory {
+		return ErrExportParamInvalid
+	}
+
+	return nil
+}
+
+// key stretches passphrase normalized by NFKD as the bip-39 passphrase
+func (params ExportParams) key(passphrase string, salt []byte) []byte {
+	normalized := []byte(norm.NFKD.String(passphrase))
+	return argon2.IDKey(normalized, salt, uint32(params.Time), params.Memory, params.Threads, chacha20poly1305.KeySize)
+}
@@ -0,0 +1,100 @@
+package mderive
+
+import (
+	"strings"
+	"testing"
+)
+
+// testExportParams keeps argon2id fast in tests
+var testExportParams = ExportParams{Time: 1, Memory: 64, Threads: 1}
+
+func TestExportMnemonic(t *testing.T) {
+	japanese, _ := NewMnemonicByEntropy(make([]byte, 20), Japanese)
+	mnemonics := []string{
+		"used legend breeze program soldier position toddler limb long dinosaur urge hunt",
+		japanese,
+	}
+
+	for _, mnemonic := range mnemonics {
+		text, err := exportMnemonic(mnemonic, "correct horse", testExportParams, nil)
+		if err != nil {
+			t.Fatalf("export mnemonic failed: %s", err)
+		}
+
+		if !strings.HasPrefix(text, ExportPrefix) || strings.Contains(text, strings.Fields(mnemonic)[0]) {
+			t.Fatalf("exported text should not contain words: %s", text)
+		}
+
+		imported, err := ImportMnemonic(text, "correct horse")
+		if err != nil {
+			t.Fatalf("import mnemonic failed: %s", err)
+		}
+
+		if imported != mnemonic {
+			t.Fatalf("imported mnemonic doesn't match: %s", imported)
+		}
+
+		if _, err := ImportMnemonic(text, "wrong horse"); err != ErrExportPassphraseWrong {
+			t.Fatalf("wrong passphrase should be rejected: %v", err)
+		}
+	}
+
+	abbreviated := "used lege bree prog sold posi todd limb long dino urge hunt"
+	text, _ := exportMnemonic(abbreviated, "correct horse", testExportParams, nil)
+	if imported, _ := ImportMnemonic(text, "correct horse"); imported != mnemonics[0] {
+		t.Fatalf("abbreviated mnemonic should be exported in full words: %s", imported)
+	}
+
+	if _, err := ExportMnemonic(mnemonics[0], ""); err != ErrExportPassphraseEmpty {
+		t.Fatalf("empty passphrase should be rejected")
+	}
+
+	if _, err := exportMnemonic(mnemonics[0], "pass", ExportParams{}, nil); err != ErrExportParamInvalid {
+		t.Fatalf("zero argon2id parameters should be rejected")
+	}
+}
+
+func TestImportMnemonicInvalid(t *testing.T) {
+	mnemonic := "used legend breeze program soldier position toddler limb long dinosaur urge hunt"
+	text, _ := exportMnemonic(mnemonic, "pass", testExportParams, nil)
+
+	// change one character of payload
+	last := text[len(text)-1]
+	replaced := byte('2')
+	if last == replaced {
+		replaced = '3'
+	}
+	modified := text[:len(text)-1] + string(replaced)
+	if _, err := ImportMnemonic(modified, "pass"); err != ErrInvalidChecksum {
+		t.Fatalf("modified text should fail checksum: %v", err)
+	}
+
+	if _, err := ImportMnemonic(strings.TrimPrefix(text, ExportPrefix), "pass"); err != ErrExportFormatInvalid {
+		t.Fatalf("text without prefix should be rejected: %v", err)
+	}
+
+	if _, err := ImportMnemonic(ExportPrefix+"abc", "pass"); err != ErrExportFormatInvalid {
+		t.Fatalf("short text should be rejected: %v", err)
+	}
+
+	payload, _ := b58Decode(strings.TrimPrefix(text, ExportPrefix))
+	payload[0] = 2
+	payload, _ = addChecksumToBytes(payload[:len(payload)-4])
+	if _, err := ImportMnemonic(ExportPrefix+b58Encode(payload), "pass"); err != ErrExportVersionInvalid {
+		t.Fatalf("unknown version should be rejected: %v", err)
+	}
+
+	// a forged header with a valid checksum must not make argon2id run
+	forged := [][]byte{
+		{exportVersion, 1, 0x00, 0x20, 0x00, 0x00, 1}, // 2 GiB memory
+		{exportVersion, 255, 0x00, 0x00, 0x00, 0x40, 1},
+	}
+	for _, header := range forged {
+		payload, _ = b58Decode(strings.TrimPrefix(text, ExportPrefix))
+		copy(payload, header)
+		payload, _ = addChecksumToBytes(payload[:len(payload)-4])
+		if _, err := ImportMnemonic(ExportPrefix+b58Encode(payload), "pass"); err != ErrExportParamInvalid {
+			t.Fatalf("expensive argon2id parameters should be rejected: %v", err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package mderive
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// false positive probability of health tests, as recommended by NIST SP 800-90B
+	healthTestAlpha = 1.0 / (1 << 20)
+
+	// assumed min-entropy bits per byte of a healthy source
+	defaultMinEntropy = 4
+
+	adaptiveProportionWindow = 512
+)
+
+// HealthTest checks a sample read from entropy source and returns an error
+// wrapping ErrEntropyHealthTest if the sample looks broken.
+type HealthTest func(sample []byte) error
+
+// EntropySource is an io.Reader which reads entropy from Reader and runs health
+// tests on every read sample, it can be used by NewEntropyFromReader and
+// NewMnemonicFromReader to reject broken entropy before a mnemonic is created.
+type EntropySource struct {
+	Reader      io.Reader
+	HealthTests []HealthTest
+}
+
+// NewEntropySource wraps reader with the default health tests.
+func NewEntropySource(reader io.Reader) *EntropySource {
+	return &EntropySource{
+		Reader:      reader,
+		HealthTests: DefaultHealthTests(),
+	}
+}
+
+func (s *EntropySource) Read(p []byte) (int, error) {
+	n, err := io.ReadFull(s.Reader, p)
+	if err != nil {
+		return n, err
+	}
+
+	for _, test := range s.HealthTests {
+		if err := test(p); err != nil {
+			return 0, err
+		}
+	}
+
+	return n, nil
+}
+
+// DefaultHealthTests returns repetition count, adaptive proportion and monobit
+// tests assuming 4 bits min-entropy per byte.
+func DefaultHealthTests() []HealthTest {
+	return []HealthTest{
+		RepetitionCountTest(defaultMinEntropy),
+		AdaptiveProportionTest(defaultMinEntropy),
+		MonobitTest(),
+	}
+}
+
+// RepetitionCountTest detects a source stuck on a single value, it fails if a
+// byte repeats 1 + ceil(20 / minEntropy) times in a row (NIST SP 800-90B 4.4.1).
+func RepetitionCountTest(minEntropy float64) HealthTest {
+	cutoff := 1 + int(math.Ceil(-math.Log2(healthTestAlpha)/minEntropy))
+
+	return func(sample []byte) error {
+		run := 1
+		for i := 1; i < len(sample); i++ {
+			if sample[i] != sample[i-1] {
+				run = 1
+				continue
+			}
+
+			run++
+			if run >= cutoff {
+				return fmt.Errorf("%w: byte %#02x repeats %d times", ErrEntropyHealthTest, sample[i], run)
+			}
+		}
+
+		return nil
+	}
+}
+
+// AdaptiveProportionTest detects a large loss of entropy, it fails if the first
+// byte of a window occurs too often in the window (NIST SP 800-90B 4.4.2).
+func AdaptiveProportionTest(minEntropy float64) HealthTest {
+	p := math.Pow(2, -minEntropy)
+
+	return func(sample []byte) error {
+		for start := 0; start < len(sample); start += adaptiveProportionWindow {
+			window := sample[start:min(start+adaptiveProportionWindow, len(sample))]
+			cutoff := binomialCutoff(len(window)-1, p)
+
+			count := 0
+			for _, b := range window[1:] {
+				if b == window[0] {
+					count++
+				}
+			}
+
+			if count >= cutoff {
+				return fmt.Errorf("%w: byte %#02x occurs %d times in %d bytes", ErrEntropyHealthTest, window[0], count+1, len(window))
+			}
+		}
+
+		return nil
+	}
+}
+
+// MonobitTest detects biased bits, it fails if the proportion of ones and zeros
+// is too far from one half (NIST SP 800-22 frequency test).
+func MonobitTest() HealthTest {
+	return func(sample []byte) error {
+		n := len(sample) * 8
+		if n == 0 {
+			return nil
+		}
+
+		ones := 0
+		for _, b := range sample {
+			for ; b > 0; b &= b - 1 {
+				ones++
+			}
+		}
+
+		s := math.Abs(float64(2*ones-n)) / math.Sqrt(float64(n))
+		if math.Erfc(s/math.Sqrt2) < healthTestAlpha {
+			return fmt.Errorf("%w: %d ones in %d bits", ErrEntropyHealthTest, ones, n)
+		}
+
+		return nil
+	}
+}
+
+// binomialCutoff returns the smallest c that P(X >= c) <= healthTestAlpha, X ~ B(n, p)
+func binomialCutoff(n int, p float64) int {
+	tail := 0.0
+	for c := n; c >= 0; c-- {
+		tail += binomialProbability(n, c, p)
+		if tail > healthTestAlpha {
+			return c + 1
+		}
+	}
+
+	return 0
+}
+
+func binomialProbability(n int, k int, p float64) float64 {
+	lgN, _ := math.Lgamma(float64(n + 1))
+	lgK, _ := math.Lgamma(float64(k + 1))
+	lgNK, _ := math.Lgamma(float64(n - k + 1))
+
+	return math.Exp(lgN - lgK - lgNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
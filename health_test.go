@@ -0,0 +1,77 @@
+package mderive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestNewMnemonicFromReader(t *testing.T) {
+	source := bytes.NewReader(make([]byte, 16))
+
+	newMnemonic, err := NewMnemonicFromReader(source, 12)
+	if err != nil {
+		t.Fatalf("create mnemonic from reader failed: %s", err)
+	}
+
+	expect := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	if newMnemonic != expect {
+		t.Fatalf("create wrong mnemonic from reader: %s", newMnemonic)
+	}
+
+	if _, err := NewMnemonicFromReader(bytes.NewReader(make([]byte, 8)), 12); err == nil {
+		t.Fatalf("short reader should be rejected")
+	}
+}
+
+func TestEntropySource(t *testing.T) {
+	source := NewEntropySource(rand.Reader)
+	for i := 0; i < 1000; i++ {
+		if _, err := NewEntropyFromReader(source, 256); err != nil {
+			t.Fatalf("crypto/rand failed health tests: %s", err)
+		}
+	}
+
+	_, err := NewMnemonicFromReader(NewEntropySource(bytes.NewReader(make([]byte, 32))), 24)
+	if !errors.Is(err, ErrEntropyHealthTest) {
+		t.Fatalf("zero entropy should fail health tests, got %v", err)
+	}
+}
+
+func TestHealthTests(t *testing.T) {
+	random := make([]byte, 32)
+	_, _ = rand.Read(random)
+
+	// a balanced byte repeats 6 times
+	repeated := append([]byte{}, random...)
+	copy(repeated[10:16], bytes.Repeat([]byte{0x55}, 6))
+
+	// alternating bytes, balanced bits but a single value occurs in half of samples
+	alternating := bytes.Repeat([]byte{0x00, 0xff}, 16)
+
+	// distinct bytes with 7 ones each
+	biased := bytes.Repeat([]byte{0x7f, 0xbf, 0xdf, 0xef, 0xf7, 0xfb, 0xfd, 0xfe}, 4)
+
+	cases := []struct {
+		name   string
+		test   HealthTest
+		sample []byte
+	}{
+		{"repetition count", RepetitionCountTest(defaultMinEntropy), repeated},
+		{"adaptive proportion", AdaptiveProportionTest(defaultMinEntropy), alternating},
+		{"monobit", MonobitTest(), biased},
+	}
+
+	for _, c := range cases {
+		if err := c.test(c.sample); !errors.Is(err, ErrEntropyHealthTest) {
+			t.Fatalf("%s test should fail, got %v", c.name, err)
+		}
+	}
+
+	for _, test := range DefaultHealthTests() {
+		if err := test(bytes.Repeat([]byte{0x5a, 0x3c, 0x96, 0xc3}, 8)); err != nil {
+			t.Fatalf("healthy sample failed health test: %s", err)
+		}
+	}
+}
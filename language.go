@@ -0,0 +1,274 @@
+package mderive
+
+import (
+	"fmt"
+	"github.io/decision2016/go-derived-mnemonic/wordlists"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Language identifies a bip-39 wordlist, values of the built-in languages
+// follow the language codes defined in bip-85.
+type Language int
+
+const (
+	English Language = iota
+	Japanese
+	Korean
+	Spanish
+	ChineseSimplified
+	ChineseTraditional
+	French
+	Italian
+	Czech
+
+	// Portuguese has a bip-85 language code but no built-in wordlist, it is
+	// available after its wordlist is registered by LoadWordlist.
+	Portuguese
+
+	// custom languages registered at runtime start from this value
+	firstCustomLanguage Language = 100
+)
+
+// Wordlist is an immutable bip-39 wordlist with its reverse index, it can be
+// shared by concurrent goroutines.
+type Wordlist struct {
+	language     Language
+	name         string
+	separator    string
+	words        []string
+	index        map[string]int
+	prefixLength int
+	prefixes     map[string]int
+}
+
+// the registry of wordlists, built-in wordlists are registered in init and
+// custom wordlists can be registered at runtime
+var (
+	languageMutex      sync.RWMutex
+	languageWordlists  = map[Language]*Wordlist{}
+	languageNames      = map[string]Language{}
+	nextCustomLanguage = firstCustomLanguage
+)
+
+func (lang Language) String() string {
+	if wl, err := lang.Wordlist(); err == nil {
+		return wl.name
+	}
+
+	if lang == Portuguese {
+		return "portuguese"
+	}
+
+	return fmt.Sprintf("Language(%d)", int(lang))
+}
+
+// Wordlist returns the wordlist of the language.
+func (lang Language) Wordlist() (*Wordlist, error) {
+	languageMutex.RLock()
+	defer languageMutex.RUnlock()
+
+	wl, exists := languageWordlists[lang]
+	if !exists {
+		return nil, ErrLanguageUnsupported
+	}
+
+	return wl, nil
+}
+
+// ParseLanguage returns the language by its name, e.g. "english" or "chinese_simplified".
+func ParseLanguage(name string) (Language, error) {
+	name = normalizeLanguageName(name)
+
+	languageMutex.RLock()
+	defer languageMutex.RUnlock()
+
+	lang, exists := languageNames[name]
+	if !exists {
+		return 0, ErrLanguageUnsupported
+	}
+
+	return lang, nil
+}
+
+// MarshalText saves language by its name, since ids of custom languages depend
+// on the order they are registered.
+func (lang Language) MarshalText() ([]byte, error) {
+	return []byte(lang.String()), nil
+}
+
+func (lang *Language) UnmarshalText(text []byte) error {
+	parsed, err := ParseLanguage(string(text))
+	if err != nil {
+		return err
+	}
+
+	*lang = parsed
+	return nil
+}
+
+// Languages returns all supported languages.
+func Languages() []Language {
+	wls := registeredWordlists()
+
+	langs := make([]Language, len(wls))
+	for i, wl := range wls {
+		langs[i] = wl.language
+	}
+
+	return langs
+}
+
+// registeredWordlists returns all registered wordlists ordered by language
+func registeredWordlists() []*Wordlist {
+	languageMutex.RLock()
+	defer languageMutex.RUnlock()
+
+	wls := make([]*Wordlist, 0, len(languageWordlists))
+	for _, wl := range languageWordlists {
+		wls = append(wls, wl)
+	}
+
+	sort.Slice(wls, func(i, j int) bool {
+		return wls[i].language < wls[j].language
+	})
+
+	return wls
+}
+
+func normalizeLanguageName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+}
+
+// DetectLanguage returns languages whose wordlist contains every word of mnemonic,
+// a mnemonic may match several languages since some words are shared between
+// wordlists, e.g. chinese simplified and chinese traditional.
+func DetectLanguage(mnemonic []string) ([]Language, error) {
+	matched, _ := detectLanguage(mnemonic)
+	if len(matched) == 0 {
+		return nil, ErrLanguageUndetected
+	}
+
+	langs := make([]Language, len(matched))
+	for i, wl := range matched {
+		langs[i] = wl.language
+	}
+
+	return langs, nil
+}
+
+// detectLanguage returns wordlists matching all words and the wordlist which
+// matches most words.
+func detectLanguage(mnemonic []string) ([]*Wordlist, *Wordlist) {
+	var matched []*Wordlist
+	var closest *Wordlist
+	closestCount := -1
+
+	for _, wl := range registeredWordlists() {
+		count := 0
+		for _, word := range mnemonic {
+			if _, exists := wl.Find(word); exists {
+				count++
+			}
+		}
+
+		if count == len(mnemonic) {
+			matched = append(matched, wl)
+		}
+
+		if count > closestCount {
+			closest, closestCount = wl, count
+		}
+	}
+
+	return matched, closest
+}
+
+func (wl *Wordlist) Language() Language {
+	return wl.language
+}
+
+// Word returns the word at index, the index must be in range [0, 2048).
+func (wl *Wordlist) Word(index int) string {
+	return wl.words[index]
+}
+
+// Index returns the index of word and whether word exists in wordlist,
+// word is NFKD normalized before lookup.
+func (wl *Wordlist) Index(word string) (int, bool) {
+	index, exists := wl.index[norm.NFKD.String(word)]
+	return index, exists
+}
+
+// Find returns the index of word like Index, but word can also be abbreviated
+// to its unique prefix, see PrefixLength.
+func (wl *Wordlist) Find(word string) (int, bool) {
+	word = norm.NFKD.String(word)
+	if index, exists := wl.index[word]; exists {
+		return index, true
+	}
+
+	runes := []rune(word)
+	if len(runes) < wl.prefixLength {
+		return 0, false
+	}
+
+	index, exists := wl.prefixes[string(runes[:wl.prefixLength])]
+	if !exists || !strings.HasPrefix(norm.NFKD.String(wl.words[index]), word) {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// PrefixLength returns the minimum count of leading characters which identify
+// every word in wordlist uniquely, e.g. 4 for english.
+func (wl *Wordlist) PrefixLength() int {
+	return wl.prefixLength
+}
+
+// Join joins words into a mnemonic sentence, japanese words are separated by
+// the ideographic space (U+3000) as bip-39 recommended.
+func (wl *Wordlist) Join(words []string) string {
+	return strings.Join(words, wl.separator)
+}
+
+// Words returns a copy of all words in wordlist.
+func (wl *Wordlist) Words() []string {
+	words := make([]string, len(wl.words))
+	copy(words, wl.words)
+	return words
+}
+
+func registerLanguage(lang Language, name string, words []string, separator string) *Wordlist {
+	wl := newWordlist(lang, name, words, separator)
+	languageWordlists[lang] = wl
+	languageNames[name] = lang
+	return wl
+}
+
+// unregisterLanguage removes a custom wordlist, it's used by tests to keep the
+// registry unchanged
+func unregisterLanguage(lang Language) {
+	languageMutex.Lock()
+	defer languageMutex.Unlock()
+
+	if wl, exists := languageWordlists[lang]; exists {
+		delete(languageNames, wl.name)
+		delete(languageWordlists, lang)
+	}
+}
+
+func init() {
+	registerLanguage(English, "english", wordlists.English, " ")
+	registerLanguage(Japanese, "japanese", wordlists.Japanese, "\u3000")
+	registerLanguage(Korean, "korean", wordlists.Korean, " ")
+	registerLanguage(Spanish, "spanish", wordlists.Spanish, " ")
+	registerLanguage(ChineseSimplified, "chinese_simplified", wordlists.ChineseSimplified, " ")
+	registerLanguage(ChineseTraditional, "chinese_traditional", wordlists.ChineseTraditional, " ")
+	registerLanguage(French, "french", wordlists.French, " ")
+	registerLanguage(Italian, "italian", wordlists.Italian, " ")
+	registerLanguage(Czech, "czech", wordlists.Czech, " ")
+}
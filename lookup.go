@@ -0,0 +1,341 @@
+package mderive
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	lookupChunkSize = 16
+
+	defaultLookupCount = 1000
+)
+
+// LookupOptions configures the search of LookupIndex, either Mnemonic or
+// Fingerprint of the child mnemonic should be given.
+type LookupOptions struct {
+	// Mnemonic is the child mnemonic, its word count and language narrow the search.
+	Mnemonic string
+
+	// Fingerprint is the master key fingerprint of the child mnemonic with
+	// Passphrase, every word count and language has to be checked.
+	Fingerprint []byte
+	Passphrase  string
+
+	// Start and Count select indices in [Start, Start + Count), default count is 1000.
+	Start uint32
+	Count uint32
+
+	// WordCounts and Languages limit the BIP39 application paths, default is
+	// all word counts and all languages with bip-85 codes.
+	WordCounts []int
+	Languages  []Language
+
+	// SkipLegacy skips the legacy scheme {LegacyBasePath}/{index} of the derive
+	// command, LegacyBasePath defaults to LegacyMnemonicBasePath.
+	SkipLegacy     bool
+	LegacyBasePath string
+
+	// Workers is the count of parallel goroutines, default is runtime.NumCPU().
+	Workers int
+
+	// Progress is called with count of checked and total candidates, it may be
+	// called concurrently by workers.
+	Progress func(checked, total uint64)
+}
+
+// LookupResult is the path which derives the child mnemonic.
+type LookupResult struct {
+	Path     string
+	Index    uint32
+	Language Language
+	Words    int
+	Legacy   bool
+}
+
+// lookupScheme is a parent key whose children at indices are candidates
+type lookupScheme struct {
+	parent   *Key
+	wl       *Wordlist
+	words    int
+	legacy   bool
+	basePath string
+	target   []byte
+}
+
+type lookupTask struct {
+	schemes  []lookupScheme
+	opts     LookupOptions
+	total    uint64
+	checked  atomic.Uint64
+	best     atomic.Uint64
+	failed   atomic.Bool
+	mutex    sync.Mutex
+	firstErr error
+}
+
+// LookupIndex searches the index which derives the child mnemonic from master,
+// by the BIP39 application of bip-85 and by the legacy scheme of derive command.
+// The match with the lowest index is returned, ErrLookupNotFound is returned if
+// no index in range matches.
+func LookupIndex(master *Key, opts LookupOptions) (*LookupResult, error) {
+	if opts.Count == 0 {
+		opts.Count = defaultLookupCount
+	}
+
+	if uint64(opts.Start)+uint64(opts.Count) > uint64(FirstHardenedChild) {
+		return nil, ErrDerivationPathInvalid
+	}
+
+	if opts.LegacyBasePath == "" {
+		opts.LegacyBasePath = LegacyMnemonicBasePath
+	}
+
+	schemes, err := lookupSchemes(master, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schemes) == 0 {
+		return nil, ErrLookupNotFound
+	}
+
+	task := &lookupTask{
+		schemes: schemes,
+		opts:    opts,
+		total:   uint64(len(schemes)) * uint64(opts.Count),
+	}
+	task.best.Store(math.MaxUint64)
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var next atomic.Uint64
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task.work(&next)
+		}()
+	}
+	wg.Wait()
+
+	if task.firstErr != nil {
+		return nil, task.firstErr
+	}
+
+	best := task.best.Load()
+	if best == math.MaxUint64 {
+		return nil, ErrLookupNotFound
+	}
+
+	scheme := task.schemes[best%uint64(len(task.schemes))]
+	index := opts.Start + uint32(best/uint64(len(task.schemes)))
+
+	result := &LookupResult{
+		Index:    index,
+		Language: scheme.wl.language,
+		Words:    scheme.words,
+		Legacy:   scheme.legacy,
+	}
+
+	if scheme.legacy {
+		result.Path = fmt.Sprintf("%s/%d", scheme.basePath, index)
+	} else {
+		result.Path, _ = MnemonicPath(scheme.wl.language, scheme.words, index)
+	}
+
+	return result, nil
+}
+
+// lookupSchemes derives the parent key of every word count and language to be searched
+func lookupSchemes(master *Key, opts LookupOptions) ([]lookupScheme, error) {
+	type candidate struct {
+		wl     *Wordlist
+		words  int
+		target []byte
+	}
+
+	var candidates []candidate
+	switch {
+	case opts.Mnemonic != "":
+		words := strings.Fields(opts.Mnemonic)
+		matched, _ := detectLanguage(words)
+
+		for _, wl := range matched {
+			entropy, err := entropyFromMnemonic(words, wl)
+			if err == nil && lookupLanguage(wl.language, opts.Languages) {
+				candidates = append(candidates, candidate{wl, len(words), entropy})
+			}
+		}
+
+		if len(candidates) == 0 {
+			_, err := EntropyFromMnemonic(words)
+			if err == nil {
+				err = ErrLookupNotFound
+			}
+			return nil, err
+		}
+	case len(opts.Fingerprint) == 4:
+		wordCounts := opts.WordCounts
+		if len(wordCounts) == 0 {
+			wordCounts = validMnemonicLengths
+		}
+
+		for _, wl := range registeredWordlists() {
+			if wl.language >= firstCustomLanguage || !lookupLanguage(wl.language, opts.Languages) {
+				continue
+			}
+
+			for _, words := range wordCounts {
+				candidates = append(candidates, candidate{wl: wl, words: words})
+			}
+		}
+	default:
+		return nil, ErrLookupTargetInvalid
+	}
+
+	var schemes []lookupScheme
+	for _, c := range candidates {
+		if len(opts.WordCounts) > 0 && !CheckInArr(opts.WordCounts, c.words) {
+			continue
+		}
+
+		if !opts.SkipLegacy && c.wl.language == English {
+			parent, err := DerivePrivateKey(master, opts.LegacyBasePath)
+			if err != nil {
+				return nil, err
+			}
+			schemes = append(schemes, lookupScheme{parent, c.wl, c.words, true, opts.LegacyBasePath, c.target})
+		}
+
+		if c.wl.language >= firstCustomLanguage {
+			continue
+		}
+
+		path, err := BIP85Path(AppBIP39, uint32(c.wl.language), uint32(c.words))
+		if err != nil {
+			return nil, err
+		}
+
+		parent, err := DerivePrivateKey(master, path)
+		if err != nil {
+			return nil, err
+		}
+		schemes = append(schemes, lookupScheme{parent, c.wl, c.words, false, path, c.target})
+	}
+
+	return schemes, nil
+}
+
+func lookupLanguage(lang Language, langs []Language) bool {
+	if len(langs) == 0 {
+		return true
+	}
+
+	for _, item := range langs {
+		if item == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (task *lookupTask) work(next *atomic.Uint64) {
+	for !task.failed.Load() {
+		start := next.Add(lookupChunkSize) - lookupChunkSize
+		if start >= task.total || start > task.best.Load() {
+			return
+		}
+
+		end := min(start+lookupChunkSize, task.total)
+		for n := start; n < end && n < task.best.Load(); n++ {
+			matched, err := task.match(n)
+			if err != nil {
+				task.fail(err)
+				return
+			}
+
+			if matched {
+				task.found(n)
+				break
+			}
+		}
+
+		checked := task.checked.Add(end - start)
+		if task.opts.Progress != nil {
+			task.opts.Progress(checked, task.total)
+		}
+	}
+}
+
+// match checks the n-th candidate, candidates are ordered by index and then by scheme
+func (task *lookupTask) match(n uint64) (bool, error) {
+	scheme := task.schemes[n%uint64(len(task.schemes))]
+	childIndex := task.opts.Start + uint32(n/uint64(len(task.schemes)))
+	if !scheme.legacy {
+		childIndex += FirstHardenedChild
+	}
+
+	child, err := scheme.parent.NewChild(childIndex)
+	if err != nil {
+		return false, err
+	}
+
+	entropy := entropyFromKey(child)[:scheme.words*4/3]
+	if scheme.target != nil {
+		return bytes.Equal(entropy, scheme.target), nil
+	}
+
+	m, err := newMnemonic(entropy, scheme.wl)
+	if err != nil {
+		return false, err
+	}
+
+	seed, err := m.Seed(task.opts.Passphrase)
+	if err != nil {
+		return false, err
+	}
+
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return false, err
+	}
+
+	fingerprint, err := master.Fingerprint()
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(fingerprint, task.opts.Fingerprint), nil
+}
+
+// found keeps the lowest matched candidate
+func (task *lookupTask) found(n uint64) {
+	for {
+		best := task.best.Load()
+		if n >= best || task.best.CompareAndSwap(best, n) {
+			return
+		}
+	}
+}
+
+func (task *lookupTask) fail(err error) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	if task.firstErr == nil {
+		task.firstErr = err
+	}
+	task.failed.Store(true)
+}
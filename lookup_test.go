@@ -0,0 +1,60 @@
+package mderive
+
+import (
+	"testing"
+)
+
+func TestLookupIndex(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	mnemonic, _ := DeriveMnemonic(key, English, 12, 37)
+	result, err := LookupIndex(key, LookupOptions{Mnemonic: mnemonic, Workers: 4})
+	if err != nil {
+		t.Fatalf("lookup index failed: %s", err)
+	}
+
+	if result.Index != 37 || result.Legacy || result.Path != "m/83696968'/39'/0'/12'/37'" {
+		t.Fatalf("lookup wrong index: %+v", result)
+	}
+
+	mnemonic, _ = DeriveLegacyMnemonic(key, LegacyMnemonicBasePath, 15, 5)
+	result, err = LookupIndex(key, LookupOptions{Mnemonic: mnemonic})
+	if err != nil || !result.Legacy || result.Path != "m/83696968'/0'/0'/5" || result.Words != 15 {
+		t.Fatalf("lookup wrong legacy index: %+v, %v", result, err)
+	}
+
+	if _, err := LookupIndex(key, LookupOptions{Mnemonic: mnemonic, SkipLegacy: true, Count: 50}); err != ErrLookupNotFound {
+		t.Fatalf("legacy mnemonic should not be found without legacy scheme")
+	}
+
+	if _, err := LookupIndex(key, LookupOptions{Mnemonic: mnemonic, Start: 6, Count: 10}); err != ErrLookupNotFound {
+		t.Fatalf("mnemonic should not be found out of range")
+	}
+
+	if _, err := LookupIndex(key, LookupOptions{}); err != ErrLookupTargetInvalid {
+		t.Fatalf("lookup without target should be rejected")
+	}
+}
+
+func TestLookupIndexByFingerprint(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	mnemonic, _ := DeriveMnemonic(key, Japanese, 18, 3)
+	child, _ := NewMasterKey(NewSeedByMnemonic(mnemonic, "child"))
+	fingerprint, _ := child.Fingerprint()
+
+	langs := []Language{English, Japanese}
+	result, err := LookupIndex(key, LookupOptions{Fingerprint: fingerprint, Passphrase: "child", Count: 4, Languages: langs})
+	if err != nil {
+		t.Fatalf("lookup index by fingerprint failed: %s", err)
+	}
+
+	if result.Index != 3 || result.Language != Japanese || result.Words != 18 || result.Legacy {
+		t.Fatalf("lookup wrong index by fingerprint: %+v", result)
+	}
+
+	opts := LookupOptions{Fingerprint: fingerprint, Passphrase: "child", Count: 4, Languages: langs, WordCounts: []int{12, 24}}
+	if _, err := LookupIndex(key, opts); err != ErrLookupNotFound {
+		t.Fatalf("fingerprint should not be found with other word counts")
+	}
+}
@@ -0,0 +1,111 @@
+package mderive
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mnemonic is a parsed and checksum validated bip-39 mnemonic, it carries the
+// language, entropy and word indices so it is never re-split or re-validated.
+// String and GoString are redacted, use Phrase to get the mnemonic sentence.
+// The zero value is an empty mnemonic without words and language.
+type Mnemonic struct {
+	wl      *Wordlist
+	entropy []byte
+	indices []int
+}
+
+// ParseMnemonic parses and validates mnemonic, the wordlist is detected
+// automatically if language is not given and abbreviated words are expanded.
+func ParseMnemonic(mnemonic string, lang ...Language) (*Mnemonic, error) {
+	entropy, wl, err := decodeMnemonic(strings.Fields(mnemonic), lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMnemonic(entropy, wl)
+}
+
+// MnemonicFromEntropy creates mnemonic of entropy, default language is english.
+func MnemonicFromEntropy(entropy []byte, lang ...Language) (*Mnemonic, error) {
+	wl, err := getWordlist(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMnemonic(entropy, wl)
+}
+
+func newMnemonic(entropy []byte, wl *Wordlist) (*Mnemonic, error) {
+	indices, err := entropyToIndices(entropy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mnemonic{
+		wl:      wl,
+		entropy: append([]byte{}, entropy...),
+		indices: indices,
+	}, nil
+}
+
+// Language returns the language of mnemonic, it is Language(-1) for the zero value.
+func (m Mnemonic) Language() Language {
+	if m.wl == nil {
+		return Language(-1)
+	}
+
+	return m.wl.language
+}
+
+// Entropy returns a copy of the mnemonic entropy.
+func (m Mnemonic) Entropy() []byte {
+	return append([]byte{}, m.entropy...)
+}
+
+// Indices returns a copy of the wordlist indices of words.
+func (m Mnemonic) Indices() []int {
+	return append([]int{}, m.indices...)
+}
+
+// Words returns the canonical words of mnemonic.
+func (m Mnemonic) Words() []string {
+	words := make([]string, len(m.indices))
+	for i, index := range m.indices {
+		words[i] = m.wl.Word(index)
+	}
+
+	return words
+}
+
+// Phrase returns the canonical mnemonic sentence.
+func (m Mnemonic) Phrase() string {
+	if m.wl == nil {
+		return ""
+	}
+
+	return m.wl.Join(m.Words())
+}
+
+// Seed converts mnemonic to seed with passphrase, the zero value returns
+// ErrMnemonicEmpty instead of the well-known seed of an empty sentence.
+func (m Mnemonic) Seed(passphrase string) ([]byte, error) {
+	if m.wl == nil || len(m.indices) == 0 {
+		return nil, ErrMnemonicEmpty
+	}
+
+	return NewSeedByMnemonic(m.Phrase(), passphrase), nil
+}
+
+// String returns a redacted description, so mnemonic is not leaked into logs.
+func (m Mnemonic) String() string {
+	if m.wl == nil {
+		return "Mnemonic(empty)"
+	}
+
+	return fmt.Sprintf("Mnemonic(%s, %d words, redacted)", m.wl.name, len(m.indices))
+}
+
+func (m Mnemonic) GoString() string {
+	return m.String()
+}
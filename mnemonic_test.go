@@ -0,0 +1,91 @@
+package mderive
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseMnemonic(t *testing.T) {
+	m, err := ParseMnemonic("wedd dizz inpu holl stea pig rura chim foam sket surv coyo read mate bulb")
+	if err != nil {
+		t.Fatalf("parse mnemonic failed: %s", err)
+	}
+
+	if m.Language() != English || m.Phrase() != mnemonic {
+		t.Fatalf("parse wrong mnemonic: %s", m.Phrase())
+	}
+
+	if strings.Join(m.Words(), " ") != mnemonic || len(m.Indices()) != 15 {
+		t.Fatalf("parse wrong words of mnemonic")
+	}
+
+	expect, _ := EntropyFromMnemonic(strings.Fields(mnemonic))
+	if hex.EncodeToString(m.Entropy()) != hex.EncodeToString(expect) {
+		t.Fatalf("parse wrong entropy of mnemonic")
+	}
+
+	seed, _ := m.Seed("")
+	expectSeed := "0f218c2706b677329373b9fef485db3c35129de40e4f9c6a6e9d6c767087c974586ce939197b0fd64ebe1c8077102539b889b1851ea2bffdc5436f059bb74dd2"
+	if hex.EncodeToString(seed) != expectSeed {
+		t.Fatalf("convert mnemonic to wrong seed")
+	}
+
+	if _, err := ParseMnemonic("wedding dizzy input hollow steak pig rural chimney foam sketch survey coyote ready material material"); err == nil {
+		t.Fatalf("mnemonic with wrong checksum should be rejected")
+	}
+}
+
+func TestMnemonicFromEntropy(t *testing.T) {
+	for _, vector := range japaneseVectors() {
+		entropy, _ := hex.DecodeString(vector.entropy)
+
+		m, err := MnemonicFromEntropy(entropy, Japanese)
+		if err != nil {
+			t.Fatalf("create mnemonic from entropy failed: %s", err)
+		}
+
+		seed, err := m.Seed("㍍ガバヴァぱばぐゞちぢ十人十色")
+		if err != nil || hex.EncodeToString(seed) != vector.seed {
+			t.Fatalf("convert japanese mnemonic to wrong seed")
+		}
+	}
+}
+
+func TestMnemonic_String(t *testing.T) {
+	m, _ := ParseMnemonic(mnemonic)
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v"} {
+		for _, value := range []interface{}{m, *m} {
+			output := fmt.Sprintf(format, value)
+			if strings.Contains(output, "wedding") || strings.Contains(output, hex.EncodeToString(m.Entropy())) {
+				t.Fatalf("mnemonic leaked by format %s: %s", format, output)
+			}
+		}
+	}
+
+	if m.String() != "Mnemonic(english, 15 words, redacted)" {
+		t.Fatalf("wrong redacted string: %s", m.String())
+	}
+}
+
+func TestMnemonic_ZeroValue(t *testing.T) {
+	var m Mnemonic
+
+	if output := fmt.Sprint(m); output != "Mnemonic(empty)" {
+		t.Fatalf("wrong string of zero value: %s", output)
+	}
+
+	if output := fmt.Sprintf("%#v", &m); output != "Mnemonic(empty)" {
+		t.Fatalf("wrong go string of zero value: %s", output)
+	}
+
+	if m.Language() != Language(-1) || m.Phrase() != "" || len(m.Words()) != 0 || len(m.Entropy()) != 0 {
+		t.Fatalf("zero value should have no language and words")
+	}
+
+	if seed, err := m.Seed("TREZOR"); err != ErrMnemonicEmpty || seed != nil {
+		t.Fatalf("zero value should not convert to seed, got %v", err)
+	}
+}
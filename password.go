@@ -0,0 +1,327 @@
+package mderive
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PasswordEncoding selects the bip-85 application which produces passwords.
+type PasswordEncoding int
+
+const (
+	PasswordBase85 PasswordEncoding = iota
+	PasswordBase64
+)
+
+// CharClass is a set of character classes of password.
+type CharClass uint8
+
+const (
+	Lowercase CharClass = 1 << iota
+	Uppercase
+	Digits
+	Symbols
+
+	AllClasses = Lowercase | Uppercase | Digits | Symbols
+)
+
+const (
+	defaultPasswordLength = 20
+
+	// labels which can't produce a password in these attempts are rejected
+	maxPasswordAttempts = 64
+)
+
+// base64Alphabet is the alphabet of base64.StdEncoding, padding is never a
+// password character
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+var charClassLetters = []struct {
+	class  CharClass
+	letter byte
+}{
+	{Lowercase, 'l'}, {Uppercase, 'u'}, {Digits, 'd'}, {Symbols, 's'},
+}
+
+// PasswordLabel identifies the password of a site, the same label always maps
+// to the same bip-85 index. Counter changes the password when it is rotated.
+type PasswordLabel struct {
+	Site     string           `json:"site"`
+	Username string           `json:"username,omitempty"`
+	Counter  uint32           `json:"counter"`
+	Length   int              `json:"length"`
+	Classes  CharClass        `json:"classes"`
+	Encoding PasswordEncoding `json:"encoding"`
+}
+
+// PasswordRegistry stores password labels so indices never need to be remembered,
+// it contains no secret and can be saved as plain json.
+type PasswordRegistry struct {
+	Labels []PasswordLabel `json:"labels"`
+}
+
+// Index returns the bip-85 index of the label for attempt, which is the first
+// 31 bits of sha256(site || 0 || username || 0 || counter || attempt).
+func (label PasswordLabel) Index(attempt uint32) uint32 {
+	h := sha256.New()
+	h.Write([]byte(normalizeSite(label.Site)))
+	h.Write([]byte{0})
+	h.Write([]byte(label.Username))
+	h.Write([]byte{0})
+	h.Write(uint32Bytes(label.Counter))
+	h.Write(uint32Bytes(attempt))
+
+	return binary.BigEndian.Uint32(h.Sum(nil)[:4]) & maxPathNumber
+}
+
+// DeriveSitePassword derives the password of label. The password is derived by
+// PWD BASE85 or PWD BASE64 application at the index of label, characters of
+// disallowed classes are skipped and every allowed class must appear at least
+// once, otherwise the index of next attempt is used. If a label allows all
+// classes, its password is the same as the bip-85 application at Index(0) as
+// long as that contains every class.
+//
+// Labels whose classes are expected to fill less than Length characters of
+// one encoded block, e.g. 80 digits, are rejected by ErrPasswordTooLong.
+// At most 64 attempts are made, ErrPasswordLabelInvalid is returned if none of
+// them produces a password.
+func DeriveSitePassword(key *Key, label PasswordLabel) (string, error) {
+	label = label.withDefaults()
+	if strings.TrimSpace(label.Site) == "" {
+		return "", ErrPasswordLabelInvalid
+	}
+
+	minLength, maxLength, app, alphabet := 10, 80, uint32(AppPWDBase85), base85Alphabet
+	if label.Encoding == PasswordBase64 {
+		minLength, maxLength, app, alphabet = 20, 86, AppPWDBase64, base64Alphabet
+	}
+
+	if label.Length < minLength || label.Length > maxLength || label.Classes&^AllClasses != 0 {
+		return "", ErrBIP85ParamInvalid
+	}
+
+	// maxLength is the length of one encoded block, the allowed characters in it
+	// are expected to be in proportion to their share of alphabet
+	allowed := 0
+	for i := 0; i < len(alphabet); i++ {
+		if charClassOf(alphabet[i])&label.Classes != 0 {
+			allowed++
+		}
+	}
+	if label.Length*len(alphabet) > maxLength*allowed {
+		return "", ErrPasswordTooLong
+	}
+
+	for attempt := uint32(0); attempt < maxPasswordAttempts; attempt++ {
+		entropy, err := deriveAppEntropy(key, app, uint32(label.Length), label.Index(attempt))
+		if err != nil {
+			return "", err
+		}
+
+		encoded := b85Encode(entropy)
+		if label.Encoding == PasswordBase64 {
+			encoded = base64.StdEncoding.EncodeToString(entropy)
+		}
+
+		if password, ok := filterPassword(encoded, label.Length, label.Classes); ok {
+			return password, nil
+		}
+	}
+
+	return "", ErrPasswordLabelInvalid
+}
+
+// filterPassword returns the first length characters of encoded in classes, and
+// whether it contains every class
+func filterPassword(encoded string, length int, classes CharClass) (string, bool) {
+	var password []byte
+	var found CharClass
+
+	for i := 0; i < len(encoded) && len(password) < length; i++ {
+		class := charClassOf(encoded[i])
+		if class&classes == 0 || encoded[i] == '=' {
+			continue
+		}
+
+		password = append(password, encoded[i])
+		found |= class
+	}
+
+	return string(password), len(password) == length && found == classes
+}
+
+func charClassOf(c byte) CharClass {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return Lowercase
+	case c >= 'A' && c <= 'Z':
+		return Uppercase
+	case c >= '0' && c <= '9':
+		return Digits
+	default:
+		return Symbols
+	}
+}
+
+func (label PasswordLabel) withDefaults() PasswordLabel {
+	if label.Length == 0 {
+		label.Length = defaultPasswordLength
+	}
+
+	if label.Classes == 0 {
+		label.Classes = AllClasses
+	}
+
+	return label
+}
+
+// normalizeSite makes "Example.com " and "example.com" the same site
+func normalizeSite(site string) string {
+	return strings.ToLower(strings.TrimSpace(site))
+}
+
+func (encoding PasswordEncoding) String() string {
+	if encoding == PasswordBase64 {
+		return "base64"
+	}
+
+	return "base85"
+}
+
+func (encoding PasswordEncoding) MarshalText() ([]byte, error) {
+	return []byte(encoding.String()), nil
+}
+
+func (encoding *PasswordEncoding) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "base85":
+		*encoding = PasswordBase85
+	case "base64":
+		*encoding = PasswordBase64
+	default:
+		return fmt.Errorf("unknown password encoding %s", text)
+	}
+
+	return nil
+}
+
+// String returns the letters of classes, e.g. "luds" for all classes.
+func (classes CharClass) String() string {
+	var letters []byte
+	for _, item := range charClassLetters {
+		if classes&item.class != 0 {
+			letters = append(letters, item.letter)
+		}
+	}
+
+	return string(letters)
+}
+
+// ParseCharClass parses letters of classes, l for lowercase, u for uppercase,
+// d for digits and s for symbols, e.g. "lud".
+func ParseCharClass(letters string) (CharClass, error) {
+	var classes CharClass
+	for i := 0; i < len(letters); i++ {
+		found := false
+		for _, item := range charClassLetters {
+			if letters[i] == item.letter {
+				classes |= item.class
+				found = true
+			}
+		}
+
+		if !found {
+			return 0, fmt.Errorf("unknown character class %c, it should be l, u, d or s", letters[i])
+		}
+	}
+
+	return classes, nil
+}
+
+func (classes CharClass) MarshalText() ([]byte, error) {
+	return []byte(classes.String()), nil
+}
+
+func (classes *CharClass) UnmarshalText(text []byte) error {
+	parsed, err := ParseCharClass(string(text))
+	if err != nil {
+		return err
+	}
+
+	*classes = parsed
+	return nil
+}
+
+// LoadPasswordRegistry reads the registry at path, an empty registry is
+// returned if the file doesn't exist.
+func LoadPasswordRegistry(path string) (*PasswordRegistry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &PasswordRegistry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &PasswordRegistry{}
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Save writes the registry to path, the directory is created if necessary.
+func (r *PasswordRegistry) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Find returns the label of site and username.
+func (r *PasswordRegistry) Find(site, username string) (PasswordLabel, bool) {
+	for _, label := range r.Labels {
+		if normalizeSite(label.Site) == normalizeSite(site) && label.Username == username {
+			return label, true
+		}
+	}
+
+	return PasswordLabel{}, false
+}
+
+// Put adds label or replaces the label of the same site and username, labels
+// are kept sorted by site and username.
+func (r *PasswordRegistry) Put(label PasswordLabel) {
+	label = label.withDefaults()
+	label.Site = normalizeSite(label.Site)
+
+	for idx, existing := range r.Labels {
+		if existing.Site == label.Site && existing.Username == label.Username {
+			r.Labels[idx] = label
+			return
+		}
+	}
+
+	r.Labels = append(r.Labels, label)
+	sort.Slice(r.Labels, func(i, j int) bool {
+		if r.Labels[i].Site != r.Labels[j].Site {
+			return r.Labels[i].Site < r.Labels[j].Site
+		}
+		return r.Labels[i].Username < r.Labels[j].Username
+	})
+}
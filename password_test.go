@@ -0,0 +1,110 @@
+package mderive
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeriveSitePassword(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+
+	label := PasswordLabel{Site: "example.com", Username: "alice"}
+	password, err := DeriveSitePassword(key, label)
+	if err != nil {
+		t.Fatalf("derive site password failed: %s", err)
+	}
+
+	expect, _ := DerivePasswordBase85(key, 20, label.Index(0))
+	if len(password) != 20 || password != expect {
+		t.Fatalf("site password should be the bip-85 password: %s, %s", password, expect)
+	}
+
+	same, _ := DeriveSitePassword(key, PasswordLabel{Site: " Example.COM", Username: "alice"})
+	if same != password {
+		t.Fatalf("site name should be case insensitive")
+	}
+
+	rotated, _ := DeriveSitePassword(key, PasswordLabel{Site: "example.com", Username: "alice", Counter: 1})
+	other, _ := DeriveSitePassword(key, PasswordLabel{Site: "example.com", Username: "bob"})
+	if rotated == password || other == password {
+		t.Fatalf("counter and username should change password")
+	}
+
+	label = PasswordLabel{Site: "bank.com", Length: 32, Classes: Lowercase | Digits, Encoding: PasswordBase64}
+	password, err = DeriveSitePassword(key, label)
+	if err != nil {
+		t.Fatalf("derive site password with classes failed: %s", err)
+	}
+
+	if len(password) != 32 || strings.Trim(password, "abcdefghijklmnopqrstuvwxyz0123456789") != "" ||
+		!strings.ContainsAny(password, "0123456789") {
+		t.Fatalf("site password contains wrong classes: %s", password)
+	}
+
+	if _, err := DeriveSitePassword(key, PasswordLabel{Site: "bank.com", Length: 10, Encoding: PasswordBase64}); err != ErrBIP85ParamInvalid {
+		t.Fatalf("short base64 password should be rejected")
+	}
+
+	impossible := []PasswordLabel{
+		{Site: "bank.com", Length: 80, Classes: Digits},
+		{Site: "bank.com", Length: 10, Classes: Digits},
+		{Site: "bank.com", Length: 20, Classes: Symbols, Encoding: PasswordBase64},
+	}
+	for _, label := range impossible {
+		if _, err := DeriveSitePassword(key, label); err != ErrPasswordTooLong {
+			t.Fatalf("classes which can't fill %d characters should be rejected, got %v", label.Length, err)
+		}
+	}
+
+	if _, err := DeriveSitePassword(key, PasswordLabel{}); err != ErrPasswordLabelInvalid {
+		t.Fatalf("label without site should be rejected")
+	}
+}
+
+func TestPasswordRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mderive", "passwords.json")
+
+	registry, err := LoadPasswordRegistry(path)
+	if err != nil || len(registry.Labels) != 0 {
+		t.Fatalf("load missing registry should return empty registry: %v", err)
+	}
+
+	registry.Put(PasswordLabel{Site: "Example.com", Username: "alice", Classes: Lowercase | Symbols})
+	registry.Put(PasswordLabel{Site: "bank.com", Length: 30, Encoding: PasswordBase64})
+	registry.Put(PasswordLabel{Site: "example.com", Username: "alice", Counter: 2, Classes: Lowercase | Symbols})
+
+	if err := registry.Save(path); err != nil {
+		t.Fatalf("save registry failed: %s", err)
+	}
+
+	loaded, err := LoadPasswordRegistry(path)
+	if err != nil || len(loaded.Labels) != 2 || loaded.Labels[0].Site != "bank.com" {
+		t.Fatalf("load wrong registry: %v, %v", loaded, err)
+	}
+
+	label, found := loaded.Find("EXAMPLE.com", "alice")
+	if !found || label.Counter != 2 || label.Classes != Lowercase|Symbols || label.Length != 20 {
+		t.Fatalf("find wrong label: %+v", label)
+	}
+
+	label, _ = loaded.Find("bank.com", "")
+	if label.Encoding != PasswordBase64 || label.Classes != AllClasses {
+		t.Fatalf("find wrong label: %+v", label)
+	}
+
+	if _, found := loaded.Find("example.com", "bob"); found {
+		t.Fatalf("find label of unknown username")
+	}
+}
+
+func TestParseCharClass(t *testing.T) {
+	classes, err := ParseCharClass("uld")
+	if err != nil || classes != Lowercase|Uppercase|Digits || classes.String() != "lud" {
+		t.Fatalf("parse wrong classes: %s, %v", classes, err)
+	}
+
+	if _, err := ParseCharClass("lx"); err == nil {
+		t.Fatalf("unknown class should be rejected")
+	}
+}
@@ -0,0 +1,135 @@
+package mderive
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// DerivationPath is a bip-32 path such as m/44'/0'/0'/0/1, hardened indices
+// include FirstHardenedChild. Relative paths don't start with "m" and are
+// derived from the key they are applied to, e.g. 0/1 from an account key.
+type DerivationPath struct {
+	Relative bool
+	Indices  []uint32
+}
+
+// ParseDerivationPath parses path, hardened indices are marked by ', h or H.
+// Every invalid segment is reported by a *PathError counted from 0 after "m",
+// the errors are joined by errors.Join so errors.As finds the first one.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	segments := strings.Split(path, "/")
+
+	result := DerivationPath{Relative: true}
+	if segments[0] == "m" {
+		result.Relative = false
+		segments = segments[1:]
+	}
+
+	if result.Relative && path == "" {
+		return DerivationPath{}, &PathError{Segment: 0, Err: ErrPathSegmentEmpty}
+	}
+
+	var errs []error
+	result.Indices = make([]uint32, 0, len(segments))
+	for idx, segment := range segments {
+		index, err := parsePathSegment(segment)
+		if err != nil {
+			errs = append(errs, &PathError{Segment: idx, Value: segment, Err: err})
+			continue
+		}
+		result.Indices = append(result.Indices, index)
+	}
+
+	if len(errs) != 0 {
+		return DerivationPath{}, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+func parsePathSegment(segment string) (uint32, error) {
+	if segment == "" {
+		return 0, ErrPathSegmentEmpty
+	}
+
+	hardened := uint32(0)
+	switch segment[len(segment)-1] {
+	case '\'', 'h', 'H':
+		hardened = FirstHardenedChild
+		segment = segment[:len(segment)-1]
+	}
+
+	if segment == "" {
+		return 0, ErrPathIndexInvalid
+	}
+
+	for i := 0; i < len(segment); i++ {
+		if segment[i] < '0' || segment[i] > '9' {
+			return 0, ErrPathIndexInvalid
+		}
+	}
+
+	// only digits are left, so ParseUint fails only if index is out of range
+	index, err := strconv.ParseUint(segment, 10, 32)
+	if err != nil || index >= uint64(FirstHardenedChild) {
+		return 0, ErrPathIndexOutOfRange
+	}
+
+	return uint32(index) | hardened, nil
+}
+
+// String returns the path with ' as hardened marker.
+func (p DerivationPath) String() string {
+	segments := make([]string, 0, len(p.Indices)+1)
+	if !p.Relative {
+		segments = append(segments, "m")
+	}
+
+	for _, index := range p.Indices {
+		segments = append(segments, formatPathIndex(index))
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func formatPathIndex(index uint32) string {
+	if index >= FirstHardenedChild {
+		return strconv.FormatUint(uint64(index-FirstHardenedChild), 10) + "'"
+	}
+
+	return strconv.FormatUint(uint64(index), 10)
+}
+
+// Child returns the path of child at index, hardened index includes FirstHardenedChild.
+func (p DerivationPath) Child(index uint32) DerivationPath {
+	indices := make([]uint32, len(p.Indices), len(p.Indices)+1)
+	copy(indices, p.Indices)
+
+	return DerivationPath{Relative: p.Relative, Indices: append(indices, index)}
+}
+
+// Parent returns the path without the last index.
+func (p DerivationPath) Parent() (DerivationPath, error) {
+	if len(p.Indices) == 0 {
+		return DerivationPath{}, ErrPathNoParent
+	}
+
+	indices := make([]uint32, len(p.Indices)-1)
+	copy(indices, p.Indices)
+
+	return DerivationPath{Relative: p.Relative, Indices: indices}, nil
+}
+
+// Derive derives the key at path from key, the failed segment is reported by *PathError.
+func (p DerivationPath) Derive(key *Key) (*Key, error) {
+	for idx, index := range p.Indices {
+		child, err := key.NewChild(index)
+		if err != nil {
+			return nil, &PathError{Segment: idx, Value: formatPathIndex(index), Err: err}
+		}
+		key = child
+	}
+
+	return key, nil
+}
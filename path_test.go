@@ -0,0 +1,129 @@
+package mderive
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	cases := []struct {
+		path   string
+		expect string
+		rel    bool
+		n      int
+	}{
+		{"m", "m", false, 0},
+		{"m/44'/0'/0'/0/1", "m/44'/0'/0'/0/1", false, 5},
+		{"m/44h/0H/2147483647'", "m/44'/0'/2147483647'", false, 3},
+		{"0/1", "0/1", true, 2},
+		{"2147483647", "2147483647", true, 1},
+	}
+
+	for _, c := range cases {
+		path, err := ParseDerivationPath(c.path)
+		if err != nil {
+			t.Fatalf("parse derivation path %s failed: %s", c.path, err)
+		}
+
+		if path.String() != c.expect || path.Relative != c.rel || len(path.Indices) != c.n {
+			t.Fatalf("parse derivation path %s wrong: %s", c.path, path)
+		}
+	}
+
+	invalid := []struct {
+		path    string
+		segment int
+		err     error
+	}{
+		{"", 0, ErrPathSegmentEmpty},
+		{"m/", 0, ErrPathSegmentEmpty},
+		{"m/0//1", 1, ErrPathSegmentEmpty},
+		{"m/0/1/", 2, ErrPathSegmentEmpty},
+		{"m/44'/x", 1, ErrPathIndexInvalid},
+		{"m/'", 0, ErrPathIndexInvalid},
+		{"m/-1", 0, ErrPathIndexInvalid},
+		{"m/+1", 0, ErrPathIndexInvalid},
+		{"m/1''", 0, ErrPathIndexInvalid},
+		{"m/m/0", 0, ErrPathIndexInvalid},
+		{"M/0", 0, ErrPathIndexInvalid},
+		{"m/0/2147483648", 1, ErrPathIndexOutOfRange},
+		{"m/2147483648'", 0, ErrPathIndexOutOfRange},
+		{"m/99999999999999999999", 0, ErrPathIndexOutOfRange},
+	}
+
+	for _, c := range invalid {
+		_, err := ParseDerivationPath(c.path)
+
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) || pathErr.Segment != c.segment || pathErr.Err != c.err {
+			t.Fatalf("parse derivation path %q should fail at segment %d by %v: %v", c.path, c.segment, c.err, err)
+		}
+
+		if !errors.Is(err, ErrDerivationPathInvalid) {
+			t.Fatalf("error of %q should be ErrDerivationPathInvalid", c.path)
+		}
+	}
+
+	_, err := ParseDerivationPath("m/x/0/2147483648/")
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 3 {
+		t.Fatalf("every invalid segment should be reported: %v", err)
+	}
+
+	expect := []struct {
+		segment int
+		err     error
+	}{
+		{0, ErrPathIndexInvalid}, {2, ErrPathIndexOutOfRange}, {3, ErrPathSegmentEmpty},
+	}
+	for i, e := range joined.Unwrap() {
+		var pathErr *PathError
+		if !errors.As(e, &pathErr) || pathErr.Segment != expect[i].segment || pathErr.Err != expect[i].err {
+			t.Fatalf("segment error %d should be at segment %d by %v: %v", i, expect[i].segment, expect[i].err, e)
+		}
+	}
+}
+
+func TestDerivationPathChildParent(t *testing.T) {
+	path, _ := ParseDerivationPath("m/44'/0'")
+
+	child := path.Child(FirstHardenedChild).Child(5)
+	if child.String() != "m/44'/0'/0'/5" || path.String() != "m/44'/0'" {
+		t.Fatalf("child path wrong: %s", child)
+	}
+
+	parent, err := child.Parent()
+	if err != nil || parent.String() != "m/44'/0'/0'" {
+		t.Fatalf("parent path wrong: %s", parent)
+	}
+
+	// appending to parent should not change child
+	_ = parent.Child(7)
+	if child.String() != "m/44'/0'/0'/5" {
+		t.Fatalf("child path changed by parent: %s", child)
+	}
+
+	root, _ := ParseDerivationPath("m")
+	if _, err := root.Parent(); err != ErrPathNoParent {
+		t.Fatalf("master path should have no parent")
+	}
+}
+
+func TestDerivationPathDerive(t *testing.T) {
+	master, _ := NewMasterKey(NewSeedByMnemonic(mnemonic, ""))
+
+	expect, _ := DerivePrivateKey(master, "m/44'/51'/0'/0/0")
+	account, _ := DerivePrivateKey(master, "m/44h/51H/0'")
+	key, err := DerivePrivateKey(account, "0/0")
+	if err != nil || key.String() != expect.String() {
+		t.Fatalf("relative path should derive from account key: %v", err)
+	}
+
+	path, _ := ParseDerivationPath("0/1'")
+	_, err = path.Derive(account.PublicKey())
+
+	var pathErr *PathError
+	if !errors.As(err, &pathErr) || pathErr.Segment != 1 || !errors.Is(err, ErrHardnedChildPublicKey) {
+		t.Fatalf("hardened child of public key should fail at segment 1: %v", err)
+	}
+}
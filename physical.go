@@ -0,0 +1,177 @@
+package mderive
+
+import (
+	"math"
+	"math/bits"
+	"strings"
+)
+
+var (
+	cardRanks = []string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "T", "J", "Q", "K"}
+	cardSuits = []string{"S", "H", "D", "C"}
+)
+
+const deckSize = 52
+
+// bitExtractor converts uniform random digits of any base into unbiased bits.
+// The base is split into power-of-two blocks, e.g. 6 = 4 + 2, a digit falls
+// into one block with a uniform offset, so the offset bits are uniform too.
+type bitExtractor struct {
+	data  []byte
+	count int
+}
+
+func (e *bitExtractor) add(digit int, base int) {
+	for block := 1 << (bits.Len(uint(base)) - 1); block > 0; block >>= 1 {
+		if base&block == 0 {
+			continue
+		}
+
+		if digit < block {
+			for i := bits.Len(uint(block)) - 2; i >= 0; i-- {
+				e.addBit(digit>>i&1 == 1)
+			}
+			return
+		}
+		digit -= block
+	}
+}
+
+func (e *bitExtractor) addBit(bit bool) {
+	if e.count%8 == 0 {
+		e.data = append(e.data, 0)
+	}
+
+	if bit {
+		e.data[e.count/8] |= 0x80 >> (e.count % 8)
+	}
+	e.count++
+}
+
+func (e *bitExtractor) entropy(bitSize int) ([]byte, error) {
+	if err := checkEntropyBitsLength(bitSize); err != nil {
+		return nil, err
+	}
+
+	if e.count < bitSize {
+		return nil, ErrEntropyInsufficient
+	}
+
+	return e.data[:bitSize/8], nil
+}
+
+// EntropyFromDice converts dice rolls in range [1, sides] into entropy of bitSize bits,
+// e.g. a d6 roll yields 2 bits if it is 1-4 or 1 bit if it is 5-6 to keep entropy unbiased.
+// ErrEntropyInsufficient is returned if rolls don't yield enough bits.
+func EntropyFromDice(rolls []int, sides int, bitSize int) ([]byte, error) {
+	if sides < 2 {
+		return nil, ErrDiceSidesInvalid
+	}
+
+	extractor := &bitExtractor{}
+	for _, roll := range rolls {
+		if roll < 1 || roll > sides {
+			return nil, ErrDiceRollInvalid
+		}
+		extractor.add(roll-1, sides)
+	}
+
+	return extractor.entropy(bitSize)
+}
+
+// EntropyFromCoins converts coin flips into entropy of bitSize bits, true is heads.
+func EntropyFromCoins(flips []bool, bitSize int) ([]byte, error) {
+	extractor := &bitExtractor{}
+	for _, flip := range flips {
+		extractor.addBit(flip)
+	}
+
+	return extractor.entropy(bitSize)
+}
+
+// EntropyFromCards converts the order of a shuffled deck into entropy of bitSize
+// bits, cards are written as rank and suit, e.g. "AS", "TH" or "10H", "QD", "KC".
+// The i-th card is a uniform choice of the remaining cards, a full deck yields
+// about 180 bits in average, so it is only reliable for up to 160 bits entropy.
+func EntropyFromCards(cards []string, bitSize int) ([]byte, error) {
+	remaining := make([]bool, deckSize)
+	for i := range remaining {
+		remaining[i] = true
+	}
+
+	extractor := &bitExtractor{}
+	for i, card := range cards {
+		index, err := parseCard(card)
+		if err != nil {
+			return nil, err
+		}
+
+		if !remaining[index] {
+			return nil, ErrCardDuplicated
+		}
+
+		// rank of the card among the remaining cards
+		digit := 0
+		for j := 0; j < index; j++ {
+			if remaining[j] {
+				digit++
+			}
+		}
+		remaining[index] = false
+
+		extractor.add(digit, deckSize-i)
+	}
+
+	return extractor.entropy(bitSize)
+}
+
+// MinimumRolls returns the minimum count of rolls of a dice with sides to
+// produce bitSize bits, more rolls may be required to keep entropy unbiased.
+func MinimumRolls(sides int, bitSize int) int {
+	if sides < 2 {
+		return 0
+	}
+
+	return int(math.Ceil(float64(bitSize) / math.Log2(float64(sides))))
+}
+
+// MixEntropy returns entropy XOR-ed with random bytes from crypto/rand, the
+// result is as strong as the stronger source of them.
+func MixEntropy(entropy []byte) ([]byte, error) {
+	random, err := NewEntropy(len(entropy) * 8)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range random {
+		random[i] ^= entropy[i]
+	}
+
+	return random, nil
+}
+
+func parseCard(card string) (int, error) {
+	card = strings.ToUpper(strings.TrimSpace(card))
+	if len(card) < 2 {
+		return 0, ErrCardInvalid
+	}
+
+	rank, suit := card[:len(card)-1], card[len(card)-1:]
+	if rank == "10" {
+		rank = "T"
+	}
+
+	for r, cardRank := range cardRanks {
+		if cardRank != rank {
+			continue
+		}
+
+		for s, cardSuit := range cardSuits {
+			if cardSuit == suit {
+				return s*len(cardRanks) + r, nil
+			}
+		}
+	}
+
+	return 0, ErrCardInvalid
+}
@@ -0,0 +1,139 @@
+package mderive
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestEntropyFromDice(t *testing.T) {
+	// 1-4 yield 2 bits 00, 01, 10, 11 and 5-6 yield 1 bit 0, 1
+	rolls := make([]int, 0, 128)
+	for i := 0; i < 64; i++ {
+		rolls = append(rolls, 4, 1)
+	}
+
+	entropy, err := EntropyFromDice(rolls, 6, 128)
+	if err != nil {
+		t.Fatalf("convert dice rolls to entropy failed: %s", err)
+	}
+
+	if hex.EncodeToString(entropy) != "cccccccccccccccccccccccccccccccc" {
+		t.Fatalf("convert dice rolls to wrong entropy: %x", entropy)
+	}
+
+	sixes := make([]int, 128)
+	for i := range sixes {
+		sixes[i] = 6
+	}
+
+	entropy, err = EntropyFromDice(sixes, 6, 128)
+	if err != nil {
+		t.Fatalf("convert dice rolls to entropy failed: %s", err)
+	}
+
+	if hex.EncodeToString(entropy) != "ffffffffffffffffffffffffffffffff" {
+		t.Fatalf("convert dice rolls to wrong entropy: %x", entropy)
+	}
+
+	if _, err := EntropyFromDice(sixes[:127], 6, 128); err != ErrEntropyInsufficient {
+		t.Fatalf("insufficient rolls should be rejected, got %v", err)
+	}
+
+	if _, err := EntropyFromDice([]int{7}, 6, 128); err != ErrDiceRollInvalid {
+		t.Fatalf("invalid roll should be rejected, got %v", err)
+	}
+}
+
+func TestEntropyFromD20(t *testing.T) {
+	// 1-16 yield 4 bits, 17-20 yield 2 bits
+	rolls := make([]int, 0, 64)
+	for i := 0; i < 32; i++ {
+		rolls = append(rolls, 16, 20)
+	}
+
+	entropy, err := EntropyFromDice(rolls, 20, 128)
+	if err != nil {
+		t.Fatalf("convert dice rolls to entropy failed: %s", err)
+	}
+
+	// 16 -> 1111, 20 -> 11
+	if hex.EncodeToString(entropy) != "ffffffffffffffffffffffffffffffff" {
+		t.Fatalf("convert d20 rolls to wrong entropy: %x", entropy)
+	}
+
+	if MinimumRolls(20, 128) != 30 || MinimumRolls(6, 256) != 100 {
+		t.Fatalf("wrong minimum rolls")
+	}
+}
+
+func TestEntropyFromCoins(t *testing.T) {
+	flips := make([]bool, 128)
+	for i := range flips {
+		flips[i] = i%2 == 0
+	}
+
+	entropy, err := EntropyFromCoins(flips, 128)
+	if err != nil {
+		t.Fatalf("convert coin flips to entropy failed: %s", err)
+	}
+
+	if hex.EncodeToString(entropy) != "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" {
+		t.Fatalf("convert coin flips to wrong entropy: %x", entropy)
+	}
+
+	if _, err := EntropyFromCoins(flips, 256); err != ErrEntropyInsufficient {
+		t.Fatalf("insufficient flips should be rejected, got %v", err)
+	}
+}
+
+func TestEntropyFromCards(t *testing.T) {
+	var deck []string
+	for _, suit := range cardSuits {
+		for _, rank := range cardRanks {
+			deck = append(deck, rank+suit)
+		}
+	}
+
+	// each card of an ordered deck is the first one of remaining cards, it
+	// falls into the largest block of every base and yields zero bits only
+	entropy, err := EntropyFromCards(deck, 192)
+	if err != nil {
+		t.Fatalf("convert cards to entropy failed: %s", err)
+	}
+
+	if hex.EncodeToString(entropy) != "000000000000000000000000000000000000000000000000" {
+		t.Fatalf("convert cards to wrong entropy: %x", entropy)
+	}
+
+	// each card of a reversed deck is the last one of remaining cards, it falls
+	// into the smallest block of every base and yields the fewest bits
+	reversed := make([]string, len(deck))
+	for i, card := range deck {
+		reversed[len(deck)-1-i] = card
+	}
+
+	if _, err := EntropyFromCards(reversed, 128); err != ErrEntropyInsufficient {
+		t.Fatalf("reversed deck should not yield 128 bits, got %v", err)
+	}
+
+	if _, err := EntropyFromCards([]string{"AS", "10H", "as"}, 128); err != ErrCardDuplicated {
+		t.Fatalf("duplicated card should be rejected, got %v", err)
+	}
+
+	if _, err := EntropyFromCards([]string{"1S"}, 128); err != ErrCardInvalid {
+		t.Fatalf("invalid card should be rejected, got %v", err)
+	}
+}
+
+func TestMixEntropy(t *testing.T) {
+	entropy := make([]byte, 16)
+
+	mixed, err := MixEntropy(entropy)
+	if err != nil {
+		t.Fatalf("mix entropy failed: %s", err)
+	}
+
+	if len(mixed) != 16 || hex.EncodeToString(mixed) == hex.EncodeToString(entropy) {
+		t.Fatalf("mix entropy with crypto/rand failed")
+	}
+}
@@ -0,0 +1,344 @@
+package mderive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/bits"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// UnknownWord marks a missing or unreadable word in the mnemonic passed to RecoverMnemonic
+const UnknownWord = "?"
+
+const (
+	recoverChunkSize = 4096
+
+	// 20! is the largest factorial fits in uint64
+	maxFactorial = 20
+)
+
+// RecoverOptions configures the search of RecoverMnemonic.
+type RecoverOptions struct {
+	// UnknownOrder enumerates all orders of the given words, the search space
+	// grows factorially so it is only practical for few misplaced words.
+	UnknownOrder bool
+
+	// Passphrase is used to confirm candidates by ExtendedKey or Fingerprint.
+	Passphrase string
+
+	// ExtendedKey confirms a candidate if the key derived by Path from its
+	// master key has the same public key and chain code, Path defaults to "m".
+	ExtendedKey *Key
+	Path        string
+
+	// Fingerprint confirms a candidate by the fingerprint of its master key.
+	Fingerprint []byte
+
+	// Workers is the count of parallel goroutines, default is runtime.NumCPU().
+	Workers int
+
+	// Limit stops the search after Limit mnemonics are found, 0 means no limit.
+	Limit int
+
+	// Progress is called with count of checked and total candidates, it may be
+	// called concurrently by workers.
+	Progress func(checked, total uint64)
+}
+
+type recoverTask struct {
+	wl       *Wordlist
+	indices  []int
+	unknown  int
+	opts     RecoverOptions
+	perms    uint64
+	total    uint64
+	checked  atomic.Uint64
+	found    atomic.Int64
+	failed   atomic.Bool
+	mutex    sync.Mutex
+	seen     map[string]bool
+	results  []string
+	firstErr error
+}
+
+// RecoverMnemonic enumerates candidates for the UnknownWord positions of words
+// (and for their order if opts.UnknownOrder is set), filters candidates by the
+// bip-39 checksum and returns the valid mnemonics. If opts.ExtendedKey or
+// opts.Fingerprint is given, only the confirmed mnemonics are returned.
+func RecoverMnemonic(words []string, opts RecoverOptions, lang ...Language) ([]string, error) {
+	if !CheckInArr(validMnemonicLengths, len(words)) {
+		return nil, ErrMnemonicLengthInvalid
+	}
+
+	var known []string
+	for _, word := range words {
+		if word != UnknownWord {
+			known = append(known, word)
+		}
+	}
+
+	wl, err := getRecoverWordlist(known, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &recoverTask{
+		wl:      wl,
+		indices: make([]int, len(words)),
+		opts:    opts,
+		perms:   1,
+		seen:    map[string]bool{},
+	}
+
+	for position, word := range words {
+		if word == UnknownWord {
+			task.indices[position] = -1
+			task.unknown++
+			continue
+		}
+
+		index, exists := wl.Find(word)
+		if !exists {
+			return nil, wl.wordNotFound(word, position)
+		}
+		task.indices[position] = index
+	}
+
+	if opts.UnknownOrder {
+		if len(words) > maxFactorial {
+			return nil, ErrRecoverSpaceTooLarge
+		}
+		task.perms = factorial(len(words))
+	}
+
+	task.total = task.perms
+	for i := 0; i < task.unknown; i++ {
+		hi, total := bits.Mul64(task.total, 2048)
+		if hi != 0 {
+			return nil, ErrRecoverSpaceTooLarge
+		}
+		task.total = total
+	}
+
+	if task.opts.Path == "" {
+		task.opts.Path = "m"
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var next atomic.Uint64
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task.work(&next)
+		}()
+	}
+	wg.Wait()
+
+	if task.firstErr != nil {
+		return nil, task.firstErr
+	}
+
+	sort.Strings(task.results)
+	return task.results, nil
+}
+
+func getRecoverWordlist(known []string, lang []Language) (*Wordlist, error) {
+	if len(lang) > 0 {
+		return lang[0].Wordlist()
+	}
+
+	_, closest := detectLanguage(known)
+	return closest, nil
+}
+
+func (task *recoverTask) work(next *atomic.Uint64) {
+	length := len(task.indices)
+	candidate := make([]int, length)
+	order := make([]int, length)
+	words := make([]string, length)
+
+	for !task.done() {
+		start := next.Add(recoverChunkSize) - recoverChunkSize
+		if start >= task.total {
+			return
+		}
+
+		end := min(start+recoverChunkSize, task.total)
+		for n := start; n < end && !task.done(); n++ {
+			task.candidate(n, order, candidate)
+
+			entropy, valid := indicesToEntropy(candidate)
+			if !valid {
+				continue
+			}
+
+			for i, index := range candidate {
+				words[i] = task.wl.Word(index)
+			}
+
+			confirmed, err := task.confirm(words)
+			if err != nil {
+				task.fail(err)
+				return
+			}
+
+			if confirmed {
+				task.add(string(entropy), task.wl.Join(words))
+			}
+		}
+
+		checked := task.checked.Add(end - start)
+		if task.opts.Progress != nil {
+			task.opts.Progress(checked, task.total)
+		}
+	}
+}
+
+// candidate decodes the n-th candidate, the lower digits (base 2048) select the
+// unknown words and the rest selects the permutation of words
+func (task *recoverTask) candidate(n uint64, order []int, candidate []int) {
+	unknownValues := n % (task.total / task.perms)
+	permutation := n / (task.total / task.perms)
+
+	nthPermutation(permutation, order)
+
+	for i, position := range order {
+		index := task.indices[position]
+		if index < 0 {
+			index = int(unknownValues % 2048)
+			unknownValues /= 2048
+		}
+		candidate[i] = index
+	}
+}
+
+func (task *recoverTask) confirm(words []string) (bool, error) {
+	if task.opts.ExtendedKey == nil && task.opts.Fingerprint == nil {
+		return true, nil
+	}
+
+	seed := NewSeedByMnemonic(task.wl.Join(words), task.opts.Passphrase)
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return false, err
+	}
+
+	if task.opts.Fingerprint != nil {
+		fingerprint, err := master.Fingerprint()
+		if err != nil {
+			return false, err
+		}
+
+		if !bytes.Equal(fingerprint, task.opts.Fingerprint) {
+			return false, nil
+		}
+	}
+
+	if task.opts.ExtendedKey != nil {
+		key, err := DerivePrivateKey(master, task.opts.Path)
+		if err != nil {
+			return false, err
+		}
+
+		target := task.opts.ExtendedKey.PublicKey()
+		if !bytes.Equal(key.PublicKey().Key, target.Key) || !bytes.Equal(key.ChainCode, target.ChainCode) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (task *recoverTask) add(entropy string, mnemonic string) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	// duplicated words produce the same mnemonic by different permutations
+	if task.seen[entropy] || task.done() {
+		return
+	}
+
+	task.seen[entropy] = true
+	task.results = append(task.results, mnemonic)
+	task.found.Add(1)
+}
+
+func (task *recoverTask) fail(err error) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+
+	if task.firstErr == nil {
+		task.firstErr = err
+	}
+	task.failed.Store(true)
+}
+
+func (task *recoverTask) done() bool {
+	if task.opts.Limit > 0 && task.found.Load() >= int64(task.opts.Limit) {
+		return true
+	}
+
+	return task.failed.Load()
+}
+
+// indicesToEntropy packs word indices to entropy and checks the bip-39 checksum
+func indicesToEntropy(indices []int) ([]byte, bool) {
+	bitsLength := len(indices) * 11
+	checksumLength := bitsLength / 33
+	entropyLength := (bitsLength - checksumLength) / 8
+
+	data := make([]byte, (bitsLength+7)/8)
+	bit := 0
+	for _, index := range indices {
+		for i := 10; i >= 0; i-- {
+			if index&(1<<i) != 0 {
+				data[bit/8] |= 0x80 >> (bit % 8)
+			}
+			bit++
+		}
+	}
+
+	entropy := data[:entropyLength]
+	hash := sha256.Sum256(entropy)
+
+	checksum := data[entropyLength] >> (8 - checksumLength)
+	return entropy, checksum == hash[0]>>(8-checksumLength)
+}
+
+// nthPermutation writes the n-th permutation of [0, len(order)) in lexicographic order
+func nthPermutation(n uint64, order []int) {
+	length := len(order)
+	for i := range order {
+		order[i] = i
+	}
+
+	for i := 0; i < length-1 && n > 0; i++ {
+		f := factorial(length - 1 - i)
+		j := i + int(n/f)
+		n %= f
+
+		// rotate the selected element to position i
+		selected := order[j]
+		copy(order[i+1:j+1], order[i:j])
+		order[i] = selected
+	}
+}
+
+func factorial(n int) uint64 {
+	result := uint64(1)
+	for i := 2; i <= n; i++ {
+		result *= uint64(i)
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+package mderive
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecoverMnemonic(t *testing.T) {
+	words := strings.Fields(mnemonic)
+	words[3] = UnknownWord
+
+	var progress atomic.Uint64
+	results, err := RecoverMnemonic(words, RecoverOptions{
+		Progress: func(checked, total uint64) {
+			progress.Store(checked)
+		},
+	})
+	if err != nil {
+		t.Fatalf("recover mnemonic failed: %s", err)
+	}
+
+	// 15 words mnemonic has 5 checksum bits, about 2048/32 candidates are valid
+	found := false
+	for _, result := range results {
+		found = found || result == mnemonic
+	}
+
+	if !found || len(results) < 32 || len(results) > 128 {
+		t.Fatalf("recover wrong candidates, count %d", len(results))
+	}
+
+	if progress.Load() != 2048 {
+		t.Fatalf("progress should report all 2048 candidates, got %d", progress.Load())
+	}
+}
+
+func TestRecoverMnemonicWithFingerprint(t *testing.T) {
+	seed, _ := NewSeedWithErrorCheck(mnemonic, "")
+	master, _ := NewMasterKey(seed)
+	fingerprint, _ := master.Fingerprint()
+
+	words := strings.Fields(mnemonic)
+	words[0] = UnknownWord
+
+	results, err := RecoverMnemonic(words, RecoverOptions{Fingerprint: fingerprint})
+	if err != nil {
+		t.Fatalf("recover mnemonic failed: %s", err)
+	}
+
+	if len(results) != 1 || results[0] != mnemonic {
+		t.Fatalf("recover wrong mnemonic by fingerprint: %v", results)
+	}
+}
+
+func TestRecoverMnemonicWithExtendedKey(t *testing.T) {
+	seed, _ := NewSeedWithErrorCheck(mnemonic, "")
+	master, _ := NewMasterKey(seed)
+	account, _ := DerivePrivateKey(master, "m/44'/0'/0'")
+	xpub, _ := Base58Decode(account.PublicKey().String())
+
+	words := strings.Fields(mnemonic)
+	words[14] = UnknownWord
+
+	results, err := RecoverMnemonic(words, RecoverOptions{ExtendedKey: xpub, Path: "m/44'/0'/0'"})
+	if err != nil {
+		t.Fatalf("recover mnemonic failed: %s", err)
+	}
+
+	if len(results) != 1 || results[0] != mnemonic {
+		t.Fatalf("recover wrong mnemonic by extended key: %v", results)
+	}
+}
+
+func TestRecoverMnemonicUnknownOrder(t *testing.T) {
+	seed, _ := NewSeedWithErrorCheck(mnemonic, "")
+	master, _ := NewMasterKey(seed)
+	fingerprint, _ := master.Fingerprint()
+
+	words := strings.Fields(mnemonic)
+	words[13], words[14] = words[14], words[13]
+
+	results, err := RecoverMnemonic(words, RecoverOptions{
+		UnknownOrder: true,
+		Fingerprint:  fingerprint,
+		Limit:        1,
+	})
+	if err != nil {
+		t.Fatalf("recover mnemonic failed: %s", err)
+	}
+
+	if len(results) != 1 || results[0] != mnemonic {
+		t.Fatalf("recover wrong mnemonic by order: %v", results)
+	}
+}
+
+func TestNthPermutation(t *testing.T) {
+	expects := []string{"012", "021", "102", "120", "201", "210"}
+
+	order := make([]int, 3)
+	for n, expect := range expects {
+		nthPermutation(uint64(n), order)
+
+		var builder strings.Builder
+		for _, i := range order {
+			builder.WriteByte(byte('0' + i))
+		}
+
+		if builder.String() != expect {
+			t.Fatalf("permutation %d should be %s, got %s", n, expect, builder.String())
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package mderive
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"io"
+	"math/big"
+	"time"
+)
+
+const (
+	AppRSA = 828365
+
+	rsaPublicExponent = 65537
+)
+
+// GPGCreationTime is the creation time of keys derived by DeriveGPGKey, the
+// fingerprint of a key depends on it. bip-85 fixes it to 1231006505, the time
+// of bitcoin genesis block 2009-01-03 18:05:05 UTC, instead of 0 which older
+// GnuPG versions don't handle well.
+var GPGCreationTime = time.Unix(1231006505, 0).UTC()
+
+// rounds of miller-rabin test by the bit size of candidate, the same as pycryptodome
+var millerRabinRounds = []struct {
+	bits   int
+	rounds int
+}{
+	{220, 30}, {280, 20}, {390, 15}, {512, 10}, {620, 7}, {740, 6}, {890, 5}, {1200, 4}, {1700, 3}, {3700, 2},
+}
+
+// DeriveRSA derives a RSA key by the RSA application, path is
+// m/83696968'/828365'/{bits}'/{index}'. bits is a multiple of 256 in range [1024, 8192].
+func DeriveRSA(key *Key, bits int, index uint32) (*rsa.PrivateKey, error) {
+	return deriveRSAByPath(key, bits, index)
+}
+
+// deriveRSAByPath derives a RSA key from the DRNG of m/83696968'/828365'/{bits}'/{params...}'
+func deriveRSAByPath(key *Key, bits int, params ...uint32) (*rsa.PrivateKey, error) {
+	if bits < 1024 || bits > 8192 || bits%256 != 0 {
+		return nil, ErrBIP85ParamInvalid
+	}
+
+	path, err := BIP85Path(AppRSA, append([]uint32{uint32(bits)}, params...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	drng, err := NewDRNGFromPath(key, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateRSA(drng, bits)
+}
+
+// generateRSA generates a RSA key by the random stream as pycryptodome RSA.generate
+// does, which the reference implementation of bip-85 uses with the DRNG as randfunc.
+// bits must be a multiple of 256 as pycryptodome requires.
+func generateRSA(random io.Reader, bits int) (*rsa.PrivateKey, error) {
+	if bits < 1024 || bits%256 != 0 {
+		return nil, ErrBIP85ParamInvalid
+	}
+
+	e := big.NewInt(rsaPublicExponent)
+	one := big.NewInt(1)
+
+	sizeQ := bits / 2
+	sizeP := bits - sizeQ
+	minQ := new(big.Int).Sqrt(new(big.Int).Lsh(one, uint(2*sizeQ-1)))
+	minP := new(big.Int).Sqrt(new(big.Int).Lsh(one, uint(2*sizeP-1)))
+	minDistance := new(big.Int).Lsh(one, uint(bits/2-100))
+	minD := new(big.Int).Lsh(one, uint(bits/2))
+
+	coprime := func(candidate *big.Int) bool {
+		pm1 := new(big.Int).Sub(candidate, one)
+		return new(big.Int).GCD(nil, nil, pm1, e).Cmp(one) == 0
+	}
+
+	var p, q *big.Int
+	n, d := big.NewInt(1), big.NewInt(1)
+
+	// the same loop condition as pycryptodome, keys are generated again until
+	// n has exactly bits or d isn't too small
+	for n.BitLen() != bits && d.Cmp(minD) < 0 {
+		var err error
+		p, err = generateProbablePrime(random, sizeP, func(candidate *big.Int) bool {
+			return candidate.Cmp(minP) > 0 && coprime(candidate)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		q, err = generateProbablePrime(random, sizeQ, func(candidate *big.Int) bool {
+			distance := new(big.Int).Sub(candidate, p)
+			return candidate.Cmp(minQ) > 0 && coprime(candidate) && distance.Abs(distance).Cmp(minDistance) > 0
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pm1 := new(big.Int).Sub(p, one)
+		qm1 := new(big.Int).Sub(q, one)
+		gcd := new(big.Int).GCD(nil, nil, pm1, qm1)
+		lcm := new(big.Int).Mul(pm1, qm1)
+		lcm.Div(lcm, gcd)
+
+		n = new(big.Int).Mul(p, q)
+		d = new(big.Int).ModInverse(e, lcm)
+	}
+
+	if p.Cmp(q) > 0 {
+		p, q = q, p
+	}
+
+	priv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: n, E: rsaPublicExponent},
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+	priv.Precompute()
+
+	if err := priv.Validate(); err != nil {
+		return nil, err
+	}
+
+	return priv, nil
+}
+
+// generateProbablePrime reads odd candidates of exactly bits until one passes
+// filter and the primality test
+func generateProbablePrime(random io.Reader, bits int, filter func(*big.Int) bool) (*big.Int, error) {
+	for {
+		candidate, err := randomInt(random, bits, true)
+		if err != nil {
+			return nil, err
+		}
+		candidate.SetBit(candidate, 0, 1)
+
+		if !filter(candidate) {
+			continue
+		}
+
+		prime, err := millerRabin(random, candidate)
+		if err != nil {
+			return nil, err
+		}
+
+		// lucas test after miller-rabin as pycryptodome, it doesn't consume random
+		if prime && candidate.ProbablyPrime(0) {
+			return candidate, nil
+		}
+	}
+}
+
+// millerRabin tests candidate with random bases, the rounds depend on its bit size
+func millerRabin(random io.Reader, candidate *big.Int) (bool, error) {
+	rounds := 1
+	for _, r := range millerRabinRounds {
+		if candidate.BitLen() < r.bits {
+			rounds = r.rounds
+			break
+		}
+	}
+
+	one := big.NewInt(1)
+	minusOne := new(big.Int).Sub(candidate, one)
+
+	m := new(big.Int).Set(minusOne)
+	a := 0
+	for m.Bit(0) == 0 {
+		m.Rsh(m, 1)
+		a++
+	}
+
+	// bases are in range [2, candidate - 2]
+	maximum := new(big.Int).Sub(candidate, big.NewInt(4))
+	for i := 0; i < rounds; i++ {
+		var base *big.Int
+		for {
+			value, err := randomInt(random, maximum.BitLen(), false)
+			if err != nil {
+				return false, err
+			}
+
+			if value.Cmp(maximum) <= 0 {
+				base = value.Add(value, big.NewInt(2))
+				break
+			}
+		}
+
+		z := new(big.Int).Exp(base, m, candidate)
+		if z.Cmp(one) == 0 || z.Cmp(minusOne) == 0 {
+			continue
+		}
+
+		composite := true
+		for j := 1; j < a; j++ {
+			z.Exp(z, big.NewInt(2), candidate)
+			if z.Cmp(minusOne) == 0 {
+				composite = false
+				break
+			}
+			if z.Cmp(one) == 0 {
+				return false, nil
+			}
+		}
+
+		if composite {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// randomInt reads an integer of at most bits from random, the top bit is set
+// if exact is true
+func randomInt(random io.Reader, bits int, exact bool) (*big.Int, error) {
+	buffer := make([]byte, (bits+7)/8)
+	if _, err := io.ReadFull(random, buffer); err != nil {
+		return nil, err
+	}
+
+	significant := uint(8 - (len(buffer)*8 - bits))
+	if exact {
+		buffer[0] |= 1 << (significant - 1)
+	}
+	buffer[0] &= byte(1<<significant - 1)
+
+	return new(big.Int).SetBytes(buffer), nil
+}
+
+// MarshalRSAPrivateKey encodes priv as a PKCS #1 PEM block.
+func MarshalRSAPrivateKey(priv *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(priv),
+	})
+}
+
+// subkeys of the RSA-GPG application at .../{index}'/{sub}' by their usage
+const (
+	gpgEncryptionSubkey = iota
+	gpgAuthenticationSubkey
+	gpgSigningSubkey
+)
+
+// DeriveGPGKey derives an OpenPGP key by the RSA-GPG application. The primary key
+// at m/83696968'/828365'/{bits}'/{index}' only certifies, the subkeys at
+// m/83696968'/828365'/{bits}'/{index}'/{sub}' are for encryption (0'),
+// authentication (1') and signing (2'). All keys and signatures are created at
+// GPGCreationTime.
+func DeriveGPGKey(key *Key, bits int, index uint32, name, comment, email string) (*openpgp.Entity, error) {
+	uid := packet.NewUserId(name, comment, email)
+	if uid == nil {
+		return nil, ErrGPGUserIdInvalid
+	}
+
+	primaryKey, err := deriveRSAByPath(key, bits, index)
+	if err != nil {
+		return nil, err
+	}
+
+	config := gpgConfig()
+	privateKey := packet.NewRSAPrivateKey(GPGCreationTime, primaryKey)
+	entity := &openpgp.Entity{
+		PrimaryKey: &privateKey.PublicKey,
+		PrivateKey: privateKey,
+		Identities: make(map[string]*openpgp.Identity),
+	}
+
+	isPrimaryId := true
+	selfSignature := newGPGSignature(entity.PrimaryKey, packet.SigTypePositiveCert)
+	selfSignature.IsPrimaryId = &isPrimaryId
+	selfSignature.FlagsValid = true
+	selfSignature.FlagCertify = true
+
+	if err := selfSignature.SignUserId(uid.Id, entity.PrimaryKey, entity.PrivateKey, config); err != nil {
+		return nil, err
+	}
+
+	entity.Identities[uid.Id] = &openpgp.Identity{
+		Name:          uid.Id,
+		UserId:        uid,
+		SelfSignature: selfSignature,
+		Signatures:    []*packet.Signature{selfSignature},
+	}
+
+	for _, sub := range []uint32{gpgEncryptionSubkey, gpgAuthenticationSubkey, gpgSigningSubkey} {
+		subkey, err := deriveGPGSubkey(entity, key, bits, index, sub)
+		if err != nil {
+			return nil, err
+		}
+		entity.Subkeys = append(entity.Subkeys, subkey)
+	}
+
+	return entity, nil
+}
+
+// deriveGPGSubkey derives the subkey at .../{index}'/{sub}' and binds it to the
+// primary key of entity with the usage of sub
+func deriveGPGSubkey(entity *openpgp.Entity, key *Key, bits int, index, sub uint32) (openpgp.Subkey, error) {
+	rsaKey, err := deriveRSAByPath(key, bits, index, sub)
+	if err != nil {
+		return openpgp.Subkey{}, err
+	}
+
+	config := gpgConfig()
+	privateKey := packet.NewRSAPrivateKey(GPGCreationTime, rsaKey)
+	privateKey.IsSubkey = true
+	privateKey.PublicKey.IsSubkey = true
+
+	subkey := openpgp.Subkey{
+		PublicKey:  &privateKey.PublicKey,
+		PrivateKey: privateKey,
+		Sig:        newGPGSignature(entity.PrimaryKey, packet.SigTypeSubkeyBinding),
+	}
+	subkey.Sig.FlagsValid = true
+
+	switch sub {
+	case gpgEncryptionSubkey:
+		subkey.Sig.FlagEncryptStorage = true
+		subkey.Sig.FlagEncryptCommunications = true
+	case gpgAuthenticationSubkey:
+		subkey.Sig.FlagAuthenticate = true
+	case gpgSigningSubkey:
+		subkey.Sig.FlagSign = true
+
+		// signing subkey signs the primary key back, so that another primary key can't claim it
+		subkey.Sig.EmbeddedSignature = newGPGSignature(subkey.PublicKey, packet.SigTypePrimaryKeyBinding)
+		if err := subkey.Sig.EmbeddedSignature.CrossSignKey(subkey.PublicKey, entity.PrimaryKey, privateKey, config); err != nil {
+			return openpgp.Subkey{}, err
+		}
+	}
+
+	if err := subkey.Sig.SignKey(subkey.PublicKey, entity.PrivateKey, config); err != nil {
+		return openpgp.Subkey{}, err
+	}
+
+	return subkey, nil
+}
+
+func newGPGSignature(signer *packet.PublicKey, sigType packet.SignatureType) *packet.Signature {
+	return &packet.Signature{
+		Version:      signer.Version,
+		SigType:      sigType,
+		PubKeyAlgo:   signer.PubKeyAlgo,
+		Hash:         gpgConfig().Hash(),
+		CreationTime: GPGCreationTime,
+		IssuerKeyId:  &signer.KeyId,
+	}
+}
+
+// ArmorGPGKey encodes entity as an OpenPGP armored block, the private key block
+// is returned if private is true, otherwise the public key block.
+func ArmorGPGKey(entity *openpgp.Entity, private bool) (string, error) {
+	blockType := openpgp.PublicKeyType
+	if private {
+		blockType = openpgp.PrivateKeyType
+	}
+
+	var buffer bytes.Buffer
+	writer, err := armor.Encode(&buffer, blockType, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if private {
+		err = entity.SerializePrivate(writer, gpgConfig())
+	} else {
+		err = entity.Serialize(writer)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return buffer.String() + "\n", nil
+}
+
+// gpgConfig signs at GPGCreationTime without salt notations so the signatures are deterministic
+func gpgConfig() *packet.Config {
+	randomized := false
+	return &packet.Config{
+		Time: func() time.Time {
+			return GPGCreationTime
+		},
+		NonDeterministicSignaturesViaNotation: &randomized,
+	}
+}
@@ -0,0 +1,62 @@
+package slip39
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	baseIterationCount = 10000
+	roundCount         = 4
+)
+
+// encrypt encrypts master secret by the 4 rounds Feistel network of slip-39
+func encrypt(masterSecret []byte, passphrase []byte, iterationExponent int, identifier uint16, extendable bool) []byte {
+	half := len(masterSecret) / 2
+	l, r := masterSecret[:half], masterSecret[half:]
+	salt := cipherSalt(identifier, extendable)
+
+	for i := 0; i < roundCount; i++ {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, iterationExponent, salt, r))
+	}
+
+	return append(append([]byte{}, r...), l...)
+}
+
+func decrypt(encrypted []byte, passphrase []byte, iterationExponent int, identifier uint16, extendable bool) []byte {
+	half := len(encrypted) / 2
+	l, r := encrypted[:half], encrypted[half:]
+	salt := cipherSalt(identifier, extendable)
+
+	for i := roundCount - 1; i >= 0; i-- {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, iterationExponent, salt, r))
+	}
+
+	return append(append([]byte{}, r...), l...)
+}
+
+func roundFunction(i int, passphrase []byte, iterationExponent int, salt []byte, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	iterations := (baseIterationCount << iterationExponent) / roundCount
+
+	return pbkdf2.Key(password, append(append([]byte{}, salt...), r...), iterations, len(r), sha256.New)
+}
+
+func cipherSalt(identifier uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+
+	salt := []byte(customizationNonExtendable)
+	return binary.BigEndian.AppendUint16(salt, identifier)
+}
+
+func xorBytes(a []byte, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package slip39
+
+import (
+	"fmt"
+)
+
+var (
+	ErrMasterSecretLengthInvalid = fmt.Errorf("master secret should be at least 16 bytes and an even length")
+	ErrPassphraseInvalid         = fmt.Errorf("passphrase should only contain printable ascii characters")
+	ErrIterationExponentInvalid  = fmt.Errorf("iteration exponent should in range [0, 15]")
+	ErrGroupThresholdInvalid     = fmt.Errorf("group threshold should in range [1, group count]")
+	ErrGroupCountInvalid         = fmt.Errorf("group count should in range [1, 16]")
+	ErrMemberThresholdInvalid    = fmt.Errorf("member threshold should in range [1, member count]")
+	ErrMemberCountInvalid        = fmt.Errorf("member count should in range [1, 16]")
+	ErrSingleMemberThreshold     = fmt.Errorf("member threshold 1 is only allowed with a single member share")
+
+	ErrMnemonicLengthInvalid = fmt.Errorf("mnemonic length is invalid")
+	ErrMnemonicChecksum      = fmt.Errorf("mnemonic checksum is wrong")
+	ErrMnemonicPadding       = fmt.Errorf("mnemonic padding bits are not zero")
+	ErrWordNotExists         = fmt.Errorf("word not exists in slip39 wordlist")
+
+	ErrSharesEmpty          = fmt.Errorf("the set of shares is empty")
+	ErrSharesMismatch       = fmt.Errorf("shares must have the same identifier, iteration exponent and group parameters")
+	ErrShareDuplicated      = fmt.Errorf("share index is duplicated")
+	ErrGroupsInsufficient   = fmt.Errorf("insufficient number of mnemonic groups")
+	ErrGroupsWrongCount     = fmt.Errorf("wrong number of mnemonic groups")
+	ErrMembersWrongCount    = fmt.Errorf("wrong number of mnemonics in group")
+	ErrShareDigestInvalid   = fmt.Errorf("invalid digest of the shared secret")
+	ErrShareLengthsMismatch = fmt.Errorf("all share values must have the same length")
+)
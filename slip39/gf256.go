@@ -0,0 +1,165 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+)
+
+const (
+	digestLength = 4
+	digestIndex  = 254
+	secretIndex  = 255
+)
+
+// exp and log tables of GF(256) with the Rijndael polynomial x^8 + x^4 + x^3 + x + 1,
+// the generator is x + 1
+var (
+	gfExp [255]int
+	gfLog [256]int
+)
+
+func init() {
+	poly := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = poly
+		gfLog[poly] = i
+
+		// multiply poly by x + 1
+		poly = (poly << 1) ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11b
+		}
+	}
+}
+
+type rawShare struct {
+	x     int
+	value []byte
+}
+
+// interpolate returns the value at x of the polynomial through shares by Lagrange interpolation
+func interpolate(shares []rawShare, x int) ([]byte, error) {
+	length := len(shares[0].value)
+	seen := map[int]bool{}
+
+	for _, share := range shares {
+		if seen[share.x] {
+			return nil, ErrShareDuplicated
+		}
+		seen[share.x] = true
+
+		if len(share.value) != length {
+			return nil, ErrShareLengthsMismatch
+		}
+	}
+
+	for _, share := range shares {
+		if share.x == x {
+			return share.value, nil
+		}
+	}
+
+	logProduct := 0
+	for _, share := range shares {
+		logProduct += gfLog[share.x^x]
+	}
+
+	result := make([]byte, length)
+	for _, share := range shares {
+		logBasis := logProduct - gfLog[share.x^x]
+		for _, other := range shares {
+			logBasis -= gfLog[share.x^other.x]
+		}
+		logBasis = ((logBasis % 255) + 255) % 255
+
+		for i, v := range share.value {
+			if v != 0 {
+				result[i] ^= byte(gfExp[(gfLog[v]+logBasis)%255])
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// splitSecret splits secret to count shares, any threshold of them can recover the secret
+func splitSecret(threshold int, count int, secret []byte) ([]rawShare, error) {
+	shares := make([]rawShare, 0, count)
+
+	if threshold == 1 {
+		for i := 0; i < count; i++ {
+			shares = append(shares, rawShare{x: i, value: secret})
+		}
+		return shares, nil
+	}
+
+	randomCount := threshold - 2
+	for i := 0; i < randomCount; i++ {
+		value, err := randomBytes(len(secret))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: i, value: value})
+	}
+
+	randomPart, err := randomBytes(len(secret) - digestLength)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := append(shareDigest(randomPart, secret), randomPart...)
+	baseShares := append(shares[:randomCount:randomCount],
+		rawShare{x: digestIndex, value: digest},
+		rawShare{x: secretIndex, value: secret},
+	)
+
+	for i := randomCount; i < count; i++ {
+		value, err := interpolate(baseShares, i)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: i, value: value})
+	}
+
+	return shares, nil
+}
+
+// recoverSecret recovers the secret from exact threshold shares and verifies its digest
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	if threshold == 1 {
+		return shares[0].value, nil
+	}
+
+	secret, err := interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	digestShare, err := interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := shareDigest(digestShare[digestLength:], secret)
+	if !hmac.Equal(digest, digestShare[:digestLength]) {
+		return nil, ErrShareDigestInvalid
+	}
+
+	return secret, nil
+}
+
+func shareDigest(randomPart []byte, secret []byte) []byte {
+	h := hmac.New(sha256.New, randomPart)
+	h.Write(secret)
+	return h.Sum(nil)[:digestLength]
+}
+
+func randomBytes(length int) ([]byte, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
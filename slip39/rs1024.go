@@ -0,0 +1,59 @@
+package slip39
+
+const (
+	checksumLength = 3
+
+	customizationNonExtendable = "shamir"
+	customizationExtendable    = "shamir_extendable"
+)
+
+var rs1024Generator = [10]uint32{
+	0xE0E040, 0x1C1C080, 0x3838100, 0x7070200, 0xE0E0009,
+	0x1C0C2412, 0x38086C24, 0x3090FC48, 0x21B1F890, 0x3F3F120,
+}
+
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xFFFFF)<<10 ^ uint32(v)
+		for i := 0; i < 10; i++ {
+			if (b>>i)&1 != 0 {
+				chk ^= rs1024Generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func customization(extendable bool) []int {
+	s := customizationNonExtendable
+	if extendable {
+		s = customizationExtendable
+	}
+
+	values := make([]int, len(s))
+	for i := range s {
+		values[i] = int(s[i])
+	}
+
+	return values
+}
+
+func rs1024CreateChecksum(data []int, extendable bool) []int {
+	values := append(customization(extendable), data...)
+	values = append(values, make([]int, checksumLength)...)
+	polymod := rs1024Polymod(values) ^ 1
+
+	checksum := make([]int, checksumLength)
+	for i := range checksum {
+		checksum[i] = int(polymod>>(10*(checksumLength-1-i))) & 1023
+	}
+
+	return checksum
+}
+
+func rs1024VerifyChecksum(data []int, extendable bool) bool {
+	return rs1024Polymod(append(customization(extendable), data...)) == 1
+}
@@ -0,0 +1,163 @@
+package slip39
+
+import (
+	"math/big"
+	"strings"
+)
+
+const (
+	radixBits = 10
+
+	identifierBits        = 15
+	iterationExponentBits = 4
+
+	// identifier, extendable flag and iteration exponent take 2 words, group
+	// and member parameters take 2 words
+	idExpLengthWords      = 2
+	metadataLengthWords   = idExpLengthWords + 2 + checksumLength
+	minMnemonicLengthWord = metadataLengthWords + (minSecretBytes*8+radixBits-1)/radixBits
+
+	minSecretBytes = 16
+	maxShareCount  = 16
+)
+
+// share is a single slip-39 share decoded from or encoded to a mnemonic
+type share struct {
+	identifier        uint16
+	extendable        bool
+	iterationExponent int
+	groupIndex        int
+	groupThreshold    int
+	groupCount        int
+	memberIndex       int
+	memberThreshold   int
+	value             []byte
+}
+
+// commonParameters are the parameters shared by all shares of a master secret
+type commonParameters struct {
+	identifier        uint16
+	extendable        bool
+	iterationExponent int
+	groupThreshold    int
+	groupCount        int
+}
+
+func (s *share) commonParameters() commonParameters {
+	return commonParameters{
+		identifier:        s.identifier,
+		extendable:        s.extendable,
+		iterationExponent: s.iterationExponent,
+		groupThreshold:    s.groupThreshold,
+		groupCount:        s.groupCount,
+	}
+}
+
+func (s *share) words() []string {
+	extendable := 0
+	if s.extendable {
+		extendable = 1
+	}
+
+	idExp := int(s.identifier)<<(iterationExponentBits+1) | extendable<<iterationExponentBits | s.iterationExponent
+	groupParams := s.groupIndex<<16 | (s.groupThreshold-1)<<12 | (s.groupCount-1)<<8 |
+		s.memberIndex<<4 | (s.memberThreshold - 1)
+
+	valueWordCount := (len(s.value)*8 + radixBits - 1) / radixBits
+
+	data := intToIndices(big.NewInt(int64(idExp)), idExpLengthWords)
+	data = append(data, intToIndices(big.NewInt(int64(groupParams)), 2)...)
+	data = append(data, intToIndices(new(big.Int).SetBytes(s.value), valueWordCount)...)
+	data = append(data, rs1024CreateChecksum(data, s.extendable)...)
+
+	words := make([]string, len(data))
+	for i, index := range data {
+		words[i] = Wordlist[index]
+	}
+
+	return words
+}
+
+func (s *share) mnemonic() string {
+	return strings.Join(s.words(), " ")
+}
+
+// parseShare decodes a share from mnemonic and verifies its checksum and padding
+func parseShare(mnemonic string) (*share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicLengthWord {
+		return nil, ErrMnemonicLengthInvalid
+	}
+
+	paddingLength := (radixBits * (len(words) - metadataLengthWords)) % 16
+	if paddingLength > 8 {
+		return nil, ErrMnemonicLengthInvalid
+	}
+
+	data := make([]int, len(words))
+	for i, word := range words {
+		index, exists := wordIndex[word]
+		if !exists {
+			return nil, ErrWordNotExists
+		}
+		data[i] = index
+	}
+
+	idExp := int(indicesToInt(data[:idExpLengthWords]).Int64())
+	extendable := (idExp>>iterationExponentBits)&1 == 1
+
+	if !rs1024VerifyChecksum(data, extendable) {
+		return nil, ErrMnemonicChecksum
+	}
+
+	groupParams := int(indicesToInt(data[idExpLengthWords : idExpLengthWords+2]).Int64())
+
+	s := &share{
+		identifier:        uint16(idExp >> (iterationExponentBits + 1)),
+		extendable:        extendable,
+		iterationExponent: idExp & (1<<iterationExponentBits - 1),
+		groupIndex:        groupParams >> 16,
+		groupThreshold:    (groupParams>>12)&15 + 1,
+		groupCount:        (groupParams>>8)&15 + 1,
+		memberIndex:       (groupParams >> 4) & 15,
+		memberThreshold:   groupParams&15 + 1,
+	}
+
+	if s.groupCount < s.groupThreshold {
+		return nil, ErrGroupThresholdInvalid
+	}
+
+	valueData := data[idExpLengthWords+2 : len(data)-checksumLength]
+	valueByteCount := (radixBits*len(valueData) - paddingLength) / 8
+	value := indicesToInt(valueData)
+
+	if value.BitLen() > valueByteCount*8 {
+		return nil, ErrMnemonicPadding
+	}
+
+	s.value = value.FillBytes(make([]byte, valueByteCount))
+	return s, nil
+}
+
+func intToIndices(value *big.Int, length int) []int {
+	indices := make([]int, length)
+	mask := big.NewInt(1<<radixBits - 1)
+	v := new(big.Int).Set(value)
+
+	for i := length - 1; i >= 0; i-- {
+		indices[i] = int(new(big.Int).And(v, mask).Int64())
+		v.Rsh(v, radixBits)
+	}
+
+	return indices
+}
+
+func indicesToInt(indices []int) *big.Int {
+	value := new(big.Int)
+	for _, index := range indices {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+
+	return value
+}
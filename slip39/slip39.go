@@ -0,0 +1,214 @@
+// Package slip39 implements SLIP-0039 Shamir's secret-sharing for mnemonic codes.
+// reference: https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"encoding/binary"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"strings"
+)
+
+// Group is the member threshold and member count of a share group.
+type Group struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// Config describes how a master secret is split, GroupThreshold of Groups are
+// required to recover the master secret.
+type Config struct {
+	GroupThreshold    int
+	Groups            []Group
+	IterationExponent int
+	Extendable        bool
+}
+
+// Split encrypts master secret by passphrase and splits it into mnemonic shares,
+// the result holds the member shares of each group. Master secret can be the
+// entropy returned by EntropyFromMnemonic, or a slice of the output of
+// DeriveEntropyForMnemonic, e.g. its first 16 or 32 bytes.
+func Split(masterSecret []byte, passphrase string, config Config) ([][]string, error) {
+	if len(masterSecret) < minSecretBytes || len(masterSecret)%2 != 0 {
+		return nil, ErrMasterSecretLengthInvalid
+	}
+
+	if err := checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	identifierBytes, err := randomBytes(2)
+	if err != nil {
+		return nil, err
+	}
+	identifier := binary.BigEndian.Uint16(identifierBytes) & (1<<identifierBits - 1)
+
+	encrypted := encrypt(masterSecret, []byte(passphrase), config.IterationExponent, identifier, config.Extendable)
+
+	groupShares, err := splitSecret(config.GroupThreshold, len(config.Groups), encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([][]string, len(config.Groups))
+	for _, groupShare := range groupShares {
+		group := config.Groups[groupShare.x]
+
+		memberShares, err := splitSecret(group.MemberThreshold, group.MemberCount, groupShare.value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, memberShare := range memberShares {
+			s := &share{
+				identifier:        identifier,
+				extendable:        config.Extendable,
+				iterationExponent: config.IterationExponent,
+				groupIndex:        groupShare.x,
+				groupThreshold:    config.GroupThreshold,
+				groupCount:        len(config.Groups),
+				memberIndex:       memberShare.x,
+				memberThreshold:   group.MemberThreshold,
+				value:             memberShare.value,
+			}
+			mnemonics[groupShare.x] = append(mnemonics[groupShare.x], s.mnemonic())
+		}
+	}
+
+	return mnemonics, nil
+}
+
+// Combine recovers the master secret from mnemonic shares and decrypts it by passphrase.
+func Combine(mnemonics []string, passphrase string) ([]byte, error) {
+	if err := checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	if len(mnemonics) == 0 {
+		return nil, ErrSharesEmpty
+	}
+
+	var params commonParameters
+	groups := map[int][]*share{}
+	var groupOrder []int
+
+	for i, mnemonic := range mnemonics {
+		s, err := parseShare(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			params = s.commonParameters()
+		} else if s.commonParameters() != params {
+			return nil, ErrSharesMismatch
+		}
+
+		members, exists := groups[s.groupIndex]
+		if !exists {
+			groupOrder = append(groupOrder, s.groupIndex)
+		} else if members[0].memberThreshold != s.memberThreshold {
+			return nil, ErrSharesMismatch
+		}
+		groups[s.groupIndex] = append(members, s)
+	}
+
+	if len(groups) < params.groupThreshold {
+		return nil, ErrGroupsInsufficient
+	}
+
+	if len(groups) != params.groupThreshold {
+		return nil, ErrGroupsWrongCount
+	}
+
+	groupShares := make([]rawShare, 0, len(groups))
+	for _, groupIndex := range groupOrder {
+		members := groups[groupIndex]
+		memberThreshold := members[0].memberThreshold
+
+		if len(members) != memberThreshold {
+			return nil, ErrMembersWrongCount
+		}
+
+		memberShares := make([]rawShare, len(members))
+		for i, member := range members {
+			memberShares[i] = rawShare{x: member.memberIndex, value: member.value}
+		}
+
+		groupSecret, err := recoverSecret(memberThreshold, memberShares)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, rawShare{x: groupIndex, value: groupSecret})
+	}
+
+	encrypted, err := recoverSecret(params.groupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(encrypted, []byte(passphrase), params.iterationExponent, params.identifier, params.extendable), nil
+}
+
+// SplitMnemonic splits the entropy of a bip-39 mnemonic into slip-39 shares.
+func SplitMnemonic(mnemonic string, passphrase string, config Config) ([][]string, error) {
+	entropy, err := mderive.EntropyFromMnemonic(strings.Fields(mnemonic))
+	if err != nil {
+		return nil, err
+	}
+
+	return Split(entropy, passphrase, config)
+}
+
+// CombineMnemonic recovers the bip-39 mnemonic split by SplitMnemonic.
+func CombineMnemonic(mnemonics []string, passphrase string, lang ...mderive.Language) (string, error) {
+	entropy, err := Combine(mnemonics, passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	return mderive.NewMnemonicByEntropy(entropy, lang...)
+}
+
+func (config *Config) validate() error {
+	if config.IterationExponent < 0 || config.IterationExponent >= 1<<iterationExponentBits {
+		return ErrIterationExponentInvalid
+	}
+
+	if len(config.Groups) < 1 || len(config.Groups) > maxShareCount {
+		return ErrGroupCountInvalid
+	}
+
+	if config.GroupThreshold < 1 || config.GroupThreshold > len(config.Groups) {
+		return ErrGroupThresholdInvalid
+	}
+
+	for _, group := range config.Groups {
+		if group.MemberCount < 1 || group.MemberCount > maxShareCount {
+			return ErrMemberCountInvalid
+		}
+
+		if group.MemberThreshold < 1 || group.MemberThreshold > group.MemberCount {
+			return ErrMemberThresholdInvalid
+		}
+
+		if group.MemberThreshold == 1 && group.MemberCount > 1 {
+			return ErrSingleMemberThreshold
+		}
+	}
+
+	return nil
+}
+
+func checkPassphrase(passphrase string) error {
+	for i := 0; i < len(passphrase); i++ {
+		if passphrase[i] < 32 || passphrase[i] > 126 {
+			return ErrPassphraseInvalid
+		}
+	}
+
+	return nil
+}
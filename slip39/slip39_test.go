@@ -0,0 +1,212 @@
+package slip39
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	mderive "github.io/decision2016/go-derived-mnemonic"
+	"os"
+	"testing"
+)
+
+// slip39Vector is an entry of testdata/vectors.json in the format of
+// https://github.com/trezor/python-shamir-mnemonic/blob/master/vectors.json:
+// description, mnemonics, master secret and bip-32 master xprv of the secret.
+// master secret and xprv are empty if the mnemonics should be rejected.
+// testdata/vectors.json only holds entries copied from the upstream file and
+// is meant to be replaced by it verbatim, no entry is generated locally.
+type slip39Vector struct {
+	description  string
+	mnemonics    []string
+	masterSecret string
+	xprv         string
+}
+
+func (v *slip39Vector) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &[]interface{}{&v.description, &v.mnemonics, &v.masterSecret, &v.xprv})
+}
+
+func loadVectors(t *testing.T) []slip39Vector {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("read vectors failed: %s", err)
+	}
+
+	var vectors []slip39Vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("parse vectors failed: %s", err)
+	}
+
+	return vectors
+}
+
+func TestCombineVectors(t *testing.T) {
+	for _, vector := range loadVectors(t) {
+		masterSecret, err := Combine(vector.mnemonics, "TREZOR")
+		if vector.masterSecret == "" {
+			if err == nil {
+				t.Fatalf("%s: invalid mnemonics should be rejected", vector.description)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: combine failed: %s", vector.description, err)
+		}
+
+		if hex.EncodeToString(masterSecret) != vector.masterSecret {
+			t.Fatalf("%s: combine wrong master secret", vector.description)
+		}
+
+		master, err := mderive.NewMasterKey(masterSecret)
+		if err != nil || master.String() != vector.xprv {
+			t.Fatalf("%s: wrong master key of master secret", vector.description)
+		}
+	}
+}
+
+func TestCombineInvalidErrors(t *testing.T) {
+	vectors := map[string]slip39Vector{}
+	for _, vector := range loadVectors(t) {
+		vectors[vector.description] = vector
+	}
+
+	cases := []struct {
+		description string
+		err         error
+	}{
+		{"2. Mnemonic with invalid checksum (128 bits)", ErrMnemonicChecksum},
+		{"3. Mnemonic with invalid padding (128 bits)", ErrMnemonicPadding},
+		{"5. Basic sharing 2-of-3, insufficient number of shares (128 bits)", ErrMembersWrongCount},
+		{"6. Mnemonics with different identifiers (128 bits)", ErrSharesMismatch},
+	}
+
+	for _, c := range cases {
+		vector, exists := vectors[c.description]
+		if !exists {
+			t.Fatalf("vector %q not found", c.description)
+		}
+
+		if _, err := Combine(vector.mnemonics, "TREZOR"); err != c.err {
+			t.Fatalf("%s: expect error %v, got %v", c.description, c.err, err)
+		}
+	}
+}
+
+func TestSplitAndCombine(t *testing.T) {
+	masterSecret, _ := hex.DecodeString("0c94e1a8d5b2a72b7c4f0e1df3a5b6c7")
+	config := Config{
+		GroupThreshold: 2,
+		Groups: []Group{
+			{MemberThreshold: 1, MemberCount: 1},
+			{MemberThreshold: 2, MemberCount: 3},
+			{MemberThreshold: 3, MemberCount: 5},
+		},
+		Extendable: true,
+	}
+
+	groups, err := Split(masterSecret, "passphrase", config)
+	if err != nil {
+		t.Fatalf("split master secret failed: %s", err)
+	}
+
+	for i, group := range config.Groups {
+		if len(groups[i]) != group.MemberCount {
+			t.Fatalf("group %d should have %d shares, got %d", i, group.MemberCount, len(groups[i]))
+		}
+	}
+
+	selections := [][]string{
+		{groups[0][0], groups[1][0], groups[1][2]},
+		{groups[1][1], groups[2][4], groups[1][0], groups[2][0], groups[2][2]},
+	}
+
+	for _, selection := range selections {
+		recovered, err := Combine(selection, "passphrase")
+		if err != nil {
+			t.Fatalf("combine shares failed: %s", err)
+		}
+
+		if hex.EncodeToString(recovered) != hex.EncodeToString(masterSecret) {
+			t.Fatalf("combine wrong master secret")
+		}
+	}
+
+	recovered, err := Combine(selections[0], "wrong passphrase")
+	if err != nil {
+		t.Fatalf("combine shares failed: %s", err)
+	}
+
+	if hex.EncodeToString(recovered) == hex.EncodeToString(masterSecret) {
+		t.Fatalf("wrong passphrase should decrypt to another master secret")
+	}
+
+	if _, err := Combine([]string{groups[1][0], groups[2][0]}, "passphrase"); err != ErrMembersWrongCount {
+		t.Fatalf("insufficient member shares should be rejected, got %v", err)
+	}
+}
+
+func TestSplitMnemonic(t *testing.T) {
+	mnemonic := "wedding dizzy input hollow steak pig rural chimney foam sketch survey coyote ready material bulb"
+	config := Config{GroupThreshold: 1, Groups: []Group{{MemberThreshold: 2, MemberCount: 3}}}
+
+	groups, err := SplitMnemonic(mnemonic, "", config)
+	if err != nil {
+		t.Fatalf("split mnemonic failed: %s", err)
+	}
+
+	recovered, err := CombineMnemonic(groups[0][1:], "")
+	if err != nil {
+		t.Fatalf("combine mnemonic failed: %s", err)
+	}
+
+	if recovered != mnemonic {
+		t.Fatalf("combine wrong mnemonic: %s", recovered)
+	}
+}
+
+func TestSplitDerivedEntropy(t *testing.T) {
+	master, _ := mderive.Base58Decode("xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb")
+	entropy, err := mderive.DeriveEntropyForMnemonic(master, "m/83696968'/0'/0'")
+	if err != nil {
+		t.Fatalf("derive entropy failed: %s", err)
+	}
+
+	config := Config{GroupThreshold: 1, Groups: []Group{{MemberThreshold: 3, MemberCount: 5}}}
+	groups, err := Split(entropy[:32], "", config)
+	if err != nil {
+		t.Fatalf("split derived entropy failed: %s", err)
+	}
+
+	recovered, err := Combine(groups[0][2:], "")
+	if err != nil {
+		t.Fatalf("combine derived entropy failed: %s", err)
+	}
+
+	if hex.EncodeToString(recovered) != hex.EncodeToString(entropy[:32]) {
+		t.Fatalf("combine wrong derived entropy")
+	}
+}
+
+func TestSplitInvalidConfig(t *testing.T) {
+	masterSecret := make([]byte, 16)
+
+	cases := []struct {
+		config Config
+		err    error
+	}{
+		{Config{GroupThreshold: 2, Groups: []Group{{1, 1}}}, ErrGroupThresholdInvalid},
+		{Config{GroupThreshold: 1, Groups: []Group{{1, 3}}}, ErrSingleMemberThreshold},
+		{Config{GroupThreshold: 1, Groups: []Group{{4, 3}}}, ErrMemberThresholdInvalid},
+		{Config{GroupThreshold: 1, Groups: []Group{{1, 1}}, IterationExponent: 16}, ErrIterationExponentInvalid},
+	}
+
+	for _, c := range cases {
+		if _, err := Split(masterSecret, "", c.config); err != c.err {
+			t.Fatalf("expect error %v, got %v", c.err, err)
+		}
+	}
+
+	if _, err := Split(make([]byte, 15), "", cases[0].config); err != ErrMasterSecretLengthInvalid {
+		t.Fatalf("odd master secret length should be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package mderive
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+)
+
+const (
+	// edit costs are doubled so that a typo on adjacent keyboard keys costs half
+	editCost        = 2
+	adjacentKeyCost = 1
+
+	maxSuggestions    = 3
+	maxSuggestionCost = 2 * editCost
+)
+
+// qwertyNeighbors lists adjacent keys of each letter on a qwerty keyboard
+var qwertyNeighbors = map[rune]string{
+	'q': "wa", 'w': "qeas", 'e': "wrsd", 'r': "etdf", 't': "ryfg",
+	'y': "tugh", 'u': "yihj", 'i': "uojk", 'o': "ipkl", 'p': "ol",
+	'a': "qwsz", 's': "weadzx", 'd': "erfsxc", 'f': "rtdgcv", 'g': "tyfhvb",
+	'h': "yugjbn", 'j': "uihknm", 'k': "iojlm", 'l': "opk",
+	'z': "asx", 'x': "zsdc", 'c': "xdfv", 'v': "cfgb", 'b': "vghn",
+	'n': "bhjm", 'm': "njk",
+}
+
+// Suggest returns at most count words of wordlist which are closest to word,
+// the distance is the Damerau-Levenshtein distance where substitution between
+// adjacent keyboard keys is cheaper. Case is ignored, so an uppercase typo gets
+// the same suggestions. Words far away from word are not returned.
+func (wl *Wordlist) Suggest(word string, count int) []string {
+	type candidate struct {
+		index int
+		cost  int
+	}
+
+	target := []rune(strings.ToLower(norm.NFKD.String(word)))
+	var candidates []candidate
+
+	for idx, w := range wl.words {
+		cost := editDistance(target, []rune(strings.ToLower(norm.NFKD.String(w))))
+		if cost <= maxSuggestionCost {
+			candidates = append(candidates, candidate{index: idx, cost: cost})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].cost < candidates[j].cost
+	})
+
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = wl.words[c.index]
+	}
+
+	return suggestions
+}
+
+func (wl *Wordlist) wordNotFound(word string, position int) error {
+	return &WordNotFoundError{
+		Word:        word,
+		Position:    position,
+		Language:    wl.language,
+		Suggestions: wl.Suggest(word, maxSuggestions),
+	}
+}
+
+func substitutionCost(a, b rune) int {
+	if a == b {
+		return 0
+	}
+
+	for _, neighbor := range qwertyNeighbors[a] {
+		if neighbor == b {
+			return adjacentKeyCost
+		}
+	}
+
+	return editCost
+}
+
+// editDistance returns the optimal string alignment distance between a and b
+func editDistance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i * editCost
+	}
+	for j := range d[0] {
+		d[0][j] = j * editCost
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			d[i][j] = min(
+				d[i-1][j]+editCost,
+				d[i][j-1]+editCost,
+				d[i-1][j-1]+substitutionCost(a[i-1], b[j-1]),
+			)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+editCost)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
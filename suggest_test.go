@@ -0,0 +1,51 @@
+package mderive
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWordlist_Suggest(t *testing.T) {
+	wl, _ := English.Wordlist()
+
+	cases := map[string]string{
+		"weddign": "wedding",  // transposition
+		"dizzt":   "dizzy",    // adjacent key
+		"materal": "material", // missing letter
+		"chimmey": "chimney",  // adjacent key
+		"DiZZT":   "dizzy",    // adjacent key in mixed case
+		"CHIMMEY": "chimney",  // adjacent key in upper case
+	}
+
+	for typo, expect := range cases {
+		suggestions := wl.Suggest(typo, 3)
+		if len(suggestions) == 0 || suggestions[0] != expect {
+			t.Fatalf("suggest %v for %s, expect %s at first", suggestions, typo, expect)
+		}
+	}
+
+	if suggestions := wl.Suggest("zzzzzzzzzz", 3); len(suggestions) != 0 {
+		t.Fatalf("unrelated word should have no suggestion, got %v", suggestions)
+	}
+}
+
+func TestWordNotFoundError(t *testing.T) {
+	words := strings.Fields(mnemonic)
+	words[4] = "staek"
+
+	_, err := EntropyFromMnemonic(words)
+
+	var wordErr *WordNotFoundError
+	if !errors.As(err, &wordErr) {
+		t.Fatalf("misspelled word should return WordNotFoundError, got %v", err)
+	}
+
+	if wordErr.Word != "staek" || wordErr.Position != 4 || wordErr.Language != English {
+		t.Fatalf("wrong misspelled word reported: %+v", wordErr)
+	}
+
+	if len(wordErr.Suggestions) == 0 || wordErr.Suggestions[0] != "steak" {
+		t.Fatalf("wrong suggestions for misspelled word: %v", wordErr.Suggestions)
+	}
+}
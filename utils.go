@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/text/unicode/norm"
 	"io"
 	"math/big"
 )
@@ -62,13 +63,60 @@ func CheckInArr(arr []int, value int) bool {
 	return false
 }
 
-func setupWordList(wordlist []string) {
-	wordList = wordlist
-	wordMap = make(map[string]int)
+func newWordlist(lang Language, name string, words []string, separator string) *Wordlist {
+	wl := &Wordlist{
+		language:  lang,
+		name:      name,
+		separator: separator,
+		words:     words,
+		index:     make(map[string]int, len(words)),
+	}
+
+	for idx, word := range words {
+		wl.index[norm.NFKD.String(word)] = idx
+	}
+
+	wl.prefixLength, wl.prefixes = uniquePrefixes(words)
+
+	return wl
+}
+
+// uniquePrefixes returns the minimum prefix length which identifies every word
+// uniquely and the index of words by their prefix, words are compared in NFKD form
+func uniquePrefixes(words []string) (int, map[string]int) {
+	normalized := make([][]rune, len(words))
+	maxLength := 0
+	for idx, word := range words {
+		normalized[idx] = []rune(norm.NFKD.String(word))
+		if len(normalized[idx]) > maxLength {
+			maxLength = len(normalized[idx])
+		}
+	}
+
+	for length := 1; length <= maxLength; length++ {
+		prefixes := make(map[string]int, len(words))
+		for idx, word := range normalized {
+			if len(word) > length {
+				word = word[:length]
+			}
+			prefixes[string(word)] = idx
+		}
+
+		if len(prefixes) == len(words) {
+			return length, prefixes
+		}
+	}
+
+	return maxLength, map[string]int{}
+}
 
-	for idx, word := range wordlist {
-		wordMap[word] = idx
+// getWordlist returns the wordlist of optional language argument, default is english
+func getWordlist(lang []Language) (*Wordlist, error) {
+	if len(lang) == 0 {
+		return English.Wordlist()
 	}
+
+	return lang[0].Wordlist()
 }
 
 // utils for bip32
@@ -201,18 +249,21 @@ func expandPublicKey(key []byte) (*big.Int, *big.Int) {
 	return X, Y
 }
 
-func addPublicKeys(key1 []byte, key2 []byte) []byte {
-	x1, y1 := expandPublicKey(key1)
-	x2, y2 := expandPublicKey(key2)
-	return compressPublicKey(secp256k1.Add(x1, y1, x2, y2))
-}
+// addChildPublicKey returns point(il) + key, il = 0 is valid and derives the
+// same point as key
+func addChildPublicKey(il *big.Int, key []byte) ([]byte, error) {
+	x2, y2 := expandPublicKey(key)
+	if il.Sign() == 0 {
+		return compressPublicKey(x2, y2), nil
+	}
 
-func validateChildPublicKey(key []byte) error {
-	x, y := expandPublicKey(key)
+	x1, y1 := secp256k1.ScalarBaseMult(il.Bytes())
+	x, y := secp256k1.Add(x1, y1, x2, y2)
 
-	if x.Sign() == 0 || y.Sign() == 0 {
-		return ErrInvalidPublicKey
+	// Add returns (0, 0) for the point at infinity
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChildKey
 	}
 
-	return nil
+	return compressPublicKey(x, y), nil
 }
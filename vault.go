@@ -0,0 +1,285 @@
+package mderive
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/chacha20poly1305"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// AppVault is a private application number of the vault key, ascii of "VLT"
+	AppVault = 867684
+
+	vaultVersion = 1
+)
+
+// VaultEntry records how a child mnemonic is derived, so it can be derived
+// again by its label.
+type VaultEntry struct {
+	Label    string    `json:"label"`
+	Path     string    `json:"path"`
+	Index    uint32    `json:"index"`
+	Words    int       `json:"words"`
+	Language Language  `json:"language"`
+	Created  time.Time `json:"created"`
+}
+
+// Vault is a list of labelled child mnemonics, it is saved encrypted by a key
+// derived from the master key at m/83696968'/867684'/0'. The 4 bytes
+// fingerprint of the master key is saved in plaintext beside the ciphertext.
+type Vault struct {
+	Entries []VaultEntry `json:"entries"`
+}
+
+// vaultFile is the saved form of vault, the fingerprint of master key is not
+// encrypted, so it tells whether the vault belongs to a master key before
+// decryption. It is the same fingerprint as in xpubs of the master key's
+// children, but it also links the vault file to the wallet.
+type vaultFile struct {
+	Version     int    `json:"version"`
+	Fingerprint string `json:"fingerprint"`
+	Nonce       []byte `json:"nonce"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// NewVaultEntry returns the entry of the child mnemonic derived by the BIP39
+// application at index, see DeriveMnemonic.
+func NewVaultEntry(label string, lang Language, words int, index uint32) (VaultEntry, error) {
+	if strings.TrimSpace(label) == "" {
+		return VaultEntry{}, ErrVaultLabelInvalid
+	}
+
+	path, err := MnemonicPath(lang, words, index)
+	if err != nil {
+		return VaultEntry{}, err
+	}
+
+	return VaultEntry{
+		Label:    label,
+		Path:     path,
+		Index:    index,
+		Words:    words,
+		Language: lang,
+		Created:  time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Derive derives the child mnemonic of entry from master, it also derives
+// entries of legacy paths since the entropy is always truncated the same way.
+func (e VaultEntry) Derive(master *Key) (string, error) {
+	if !CheckInArr(validMnemonicLengths, e.Words) {
+		return "", ErrMnemonicLengthInvalid
+	}
+
+	entropy, err := DeriveEntropyForMnemonic(master, e.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return NewMnemonicByEntropy(entropy[:e.Words*4/3], e.Language)
+}
+
+// Add adds entry, labels are unique and entries are kept sorted by label. An
+// entry at the path of another label is rejected, since both labels would
+// derive the same mnemonic.
+func (v *Vault) Add(entry VaultEntry) error {
+	if strings.TrimSpace(entry.Label) == "" {
+		return ErrVaultLabelInvalid
+	}
+
+	if _, exists := v.Find(entry.Label); exists {
+		return ErrVaultLabelExists
+	}
+
+	for _, other := range v.Entries {
+		if other.Path == entry.Path {
+			return ErrVaultIndexUsed
+		}
+	}
+
+	v.Entries = append(v.Entries, entry)
+	sort.Slice(v.Entries, func(i, j int) bool {
+		return v.Entries[i].Label < v.Entries[j].Label
+	})
+
+	return nil
+}
+
+// Find returns the entry of label.
+func (v *Vault) Find(label string) (VaultEntry, bool) {
+	for _, entry := range v.Entries {
+		if entry.Label == label {
+			return entry, true
+		}
+	}
+
+	return VaultEntry{}, false
+}
+
+// Remove removes the entry of label and returns whether it exists.
+func (v *Vault) Remove(label string) bool {
+	for idx, entry := range v.Entries {
+		if entry.Label == label {
+			v.Entries = append(v.Entries[:idx], v.Entries[idx+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextIndex returns the smallest index not used by entries of language and
+// words, so new children don't reuse indices.
+func (v *Vault) NextIndex(lang Language, words int) uint32 {
+	used := map[uint32]bool{}
+	for _, entry := range v.Entries {
+		if entry.Language == lang && entry.Words == words {
+			used[entry.Index] = true
+		}
+	}
+
+	index := uint32(0)
+	for used[index] {
+		index++
+	}
+
+	return index
+}
+
+// OpenVault reads and decrypts the vault at path by master, an empty vault is
+// returned if the file doesn't exist.
+func OpenVault(master *Key, path string) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Vault{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return DecryptVault(master, data)
+}
+
+// Save encrypts the vault by master and writes it to path, the file is replaced
+// atomically so an interrupted save doesn't lose the vault.
+func (v *Vault) Save(master *Key, path string) error {
+	data, err := v.Encrypt(master)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Encrypt encrypts the vault by XChaCha20-Poly1305 with the key derived from master.
+func (v *Vault) Encrypt(master *Key) ([]byte, error) {
+	aead, fingerprint, err := vaultCipher(master)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	file := vaultFile{
+		Version:     vaultVersion,
+		Fingerprint: fingerprint,
+		Nonce:       make([]byte, aead.NonceSize()),
+	}
+
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return nil, err
+	}
+
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, vaultAdditionalData(file))
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+// DecryptVault decrypts the vault encrypted by Encrypt.
+func DecryptVault(master *Key, data []byte) (*Vault, error) {
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, ErrVaultCorrupted
+	}
+
+	if file.Version != vaultVersion {
+		return nil, ErrVaultVersionInvalid
+	}
+
+	aead, fingerprint, err := vaultCipher(master)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Fingerprint != fingerprint {
+		return nil, ErrVaultKeyMismatch
+	}
+
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, ErrVaultCorrupted
+	}
+
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, vaultAdditionalData(file))
+	if err != nil {
+		return nil, ErrVaultCorrupted
+	}
+
+	vault := &Vault{}
+	if err := json.Unmarshal(plaintext, vault); err != nil {
+		return nil, err
+	}
+
+	return vault, nil
+}
+
+// vaultCipher returns the cipher keyed by the vault key of master and the hex
+// fingerprint of master
+func vaultCipher(master *Key) (cipher.AEAD, string, error) {
+	entropy, err := deriveAppEntropy(master, AppVault, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	aead, err := chacha20poly1305.NewX(entropy[:chacha20poly1305.KeySize])
+	if err != nil {
+		return nil, "", err
+	}
+
+	fingerprint, err := master.Fingerprint()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return aead, hex.EncodeToString(fingerprint), nil
+}
+
+// vaultAdditionalData binds the header of vault file to the ciphertext
+func vaultAdditionalData(file vaultFile) []byte {
+	return []byte(fmt.Sprintf("mderive-vault/%d/%s", file.Version, file.Fingerprint))
+}
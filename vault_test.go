@@ -0,0 +1,99 @@
+package mderive
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVault(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+	path := filepath.Join(t.TempDir(), "mderive", "vault.json")
+
+	vault, err := OpenVault(key, path)
+	if err != nil || len(vault.Entries) != 0 {
+		t.Fatalf("open missing vault should return empty vault: %v", err)
+	}
+
+	entry, err := NewVaultEntry("exchange-cold", English, 12, vault.NextIndex(English, 12))
+	if err != nil {
+		t.Fatalf("create vault entry failed: %s", err)
+	}
+
+	if err := vault.Add(entry); err != nil {
+		t.Fatalf("add vault entry failed: %s", err)
+	}
+
+	if err := vault.Add(entry); err != ErrVaultLabelExists {
+		t.Fatalf("duplicated label should be rejected")
+	}
+
+	reused, _ := NewVaultEntry("exchange-hot", English, 12, entry.Index)
+	if err := vault.Add(reused); err != ErrVaultIndexUsed {
+		t.Fatalf("index used by another label should be rejected, got %v", err)
+	}
+
+	entry, _ = NewVaultEntry("dad's wallet", Japanese, 24, vault.NextIndex(English, 12))
+	if entry.Index != 1 {
+		t.Fatalf("next index should skip used index: %d", entry.Index)
+	}
+	_ = vault.Add(entry)
+
+	if err := vault.Save(key, path); err != nil {
+		t.Fatalf("save vault failed: %s", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("exchange-cold")) {
+		t.Fatalf("vault file should be encrypted")
+	}
+
+	loaded, err := OpenVault(key, path)
+	if err != nil || len(loaded.Entries) != 2 {
+		t.Fatalf("open saved vault failed: %v", err)
+	}
+
+	if loaded.Entries[0].Label != "dad's wallet" || loaded.Entries[0].Language != Japanese {
+		t.Fatalf("vault entries should be sorted and keep language: %v", loaded.Entries)
+	}
+
+	found, _ := loaded.Find("exchange-cold")
+	mnemonic, err := found.Derive(key)
+	if err != nil {
+		t.Fatalf("derive vault entry failed: %s", err)
+	}
+
+	expect, _ := DeriveMnemonic(key, English, 12, 0)
+	if mnemonic != expect {
+		t.Fatalf("vault entry should derive the bip-85 mnemonic: %s, %s", mnemonic, expect)
+	}
+
+	if !loaded.Remove("exchange-cold") || loaded.Remove("exchange-cold") {
+		t.Fatalf("remove should only succeed once")
+	}
+}
+
+func TestVaultDecrypt(t *testing.T) {
+	key, _ := Base58Decode(bip85MasterKey)
+	other, _ := NewMasterKey(bytes.Repeat([]byte{1}, 64))
+
+	data, err := (&Vault{}).Encrypt(key)
+	if err != nil {
+		t.Fatalf("encrypt vault failed: %s", err)
+	}
+
+	if _, err := DecryptVault(other, data); err != ErrVaultKeyMismatch {
+		t.Fatalf("vault of another master should be rejected: %v", err)
+	}
+
+	modified := bytes.Replace(data, []byte(`"ciphertext": "`), []byte(`"ciphertext": "A`), 1)
+	if _, err := DecryptVault(key, modified); err != ErrVaultCorrupted {
+		t.Fatalf("modified vault should be rejected: %v", err)
+	}
+
+	modified = bytes.Replace(data, []byte(`"version": 1`), []byte(`"version": 2`), 1)
+	if _, err := DecryptVault(key, modified); err != ErrVaultVersionInvalid {
+		t.Fatalf("unknown vault version should be rejected: %v", err)
+	}
+}
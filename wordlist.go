@@ -0,0 +1,180 @@
+package mderive
+
+import (
+	"bufio"
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"hash/crc32"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// wordlistSize is the count of words required by bip-39
+const wordlistSize = 2048
+
+// WordlistChecks are the optional checks of a custom wordlist, the word count,
+// duplicated words, whitespaces and NFKD form are always checked.
+type WordlistChecks struct {
+	// Checksum is the crc32 of wordlist file in hex, e.g. "c1dbd296" for english,
+	// empty checksum is not checked.
+	Checksum string
+
+	// Sorted requires words sorted alphabetically, accents are ignored when
+	// comparing words.
+	Sorted bool
+
+	// MaxPrefixLength requires every word to be identified uniquely by its
+	// first MaxPrefixLength characters, zero is not checked.
+	MaxPrefixLength int
+
+	// Separator joins words of a mnemonic sentence, default is space.
+	Separator string
+}
+
+// WordlistError reports a custom wordlist which failed validation, Line is the
+// 1-based line of the offending word or zero if the error concerns the whole list.
+type WordlistError struct {
+	Line   int
+	Word   string
+	Reason string
+}
+
+func (e *WordlistError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("invalid wordlist: %s", e.Reason)
+	}
+
+	return fmt.Sprintf("invalid wordlist: line %d %q %s", e.Line, e.Word, e.Reason)
+}
+
+// LoadWordlist reads a wordlist file with one word per line, validates it and
+// registers it under name, see RegisterWordlist.
+func LoadWordlist(name, path string, checks WordlistChecks) (Language, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		words = append(words, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return RegisterWordlist(name, words, checks)
+}
+
+// RegisterWordlist validates words and registers them as a language which can
+// be used everywhere a built-in language is. The wordlist named "portuguese"
+// gets the bip-85 language code of portuguese, other languages get codes from 100.
+func RegisterWordlist(name string, words []string, checks WordlistChecks) (Language, error) {
+	name = normalizeLanguageName(name)
+	if name == "" {
+		return 0, ErrLanguageNameInvalid
+	}
+
+	if err := ValidateWordlist(words, checks); err != nil {
+		return 0, err
+	}
+
+	separator := checks.Separator
+	if separator == "" {
+		separator = " "
+	}
+
+	languageMutex.Lock()
+	defer languageMutex.Unlock()
+
+	if _, exists := languageNames[name]; exists {
+		return 0, ErrLanguageExists
+	}
+
+	lang := nextCustomLanguage
+	if name == "portuguese" {
+		lang = Portuguese
+	} else {
+		nextCustomLanguage++
+	}
+
+	registerLanguage(lang, name, append([]string{}, words...), separator)
+
+	return lang, nil
+}
+
+// ValidateWordlist checks words as RegisterWordlist does without registering them.
+func ValidateWordlist(words []string, checks WordlistChecks) error {
+	if len(words) != wordlistSize {
+		return &WordlistError{Reason: fmt.Sprintf("has %d words, expected %d", len(words), wordlistSize)}
+	}
+
+	seen := make(map[string]int, len(words))
+	for idx, word := range words {
+		if word == "" {
+			return &WordlistError{Line: idx + 1, Reason: "is empty"}
+		}
+
+		if strings.IndexFunc(word, unicode.IsSpace) >= 0 {
+			return &WordlistError{Line: idx + 1, Word: word, Reason: "contains whitespace"}
+		}
+
+		if !norm.NFKD.IsNormalString(word) {
+			return &WordlistError{Line: idx + 1, Word: word, Reason: "is not in NFKD form"}
+		}
+
+		if line, exists := seen[word]; exists {
+			return &WordlistError{Line: idx + 1, Word: word, Reason: fmt.Sprintf("duplicates line %d", line)}
+		}
+		seen[word] = idx + 1
+
+		if checks.Sorted && idx > 0 && baseLetters(words[idx-1]) > baseLetters(word) {
+			return &WordlistError{Line: idx + 1, Word: word, Reason: "is not sorted"}
+		}
+	}
+
+	if checks.MaxPrefixLength > 0 {
+		if length, _ := uniquePrefixes(words); length > checks.MaxPrefixLength {
+			return &WordlistError{Reason: fmt.Sprintf("words are unique by %d characters, expected %d", length, checks.MaxPrefixLength)}
+		}
+	}
+
+	if checks.Checksum != "" {
+		expected, err := strconv.ParseUint(checks.Checksum, 16, 32)
+		if err != nil {
+			return &WordlistError{Reason: fmt.Sprintf("checksum %q is not hex", checks.Checksum)}
+		}
+
+		if actual := wordlistChecksum(words); actual != uint32(expected) {
+			return &WordlistError{Reason: fmt.Sprintf("checksum %08x doesn't match %s", actual, checks.Checksum)}
+		}
+	}
+
+	return nil
+}
+
+// Checksum returns the crc32 of wordlist file in hex, the same value is shown
+// by `crc32 english.txt` for the wordlists of bip-39 repository.
+func (wl *Wordlist) Checksum() string {
+	return fmt.Sprintf("%08x", wordlistChecksum(wl.words))
+}
+
+// wordlistChecksum returns the crc32 of words with one word per line
+func wordlistChecksum(words []string) uint32 {
+	return crc32.ChecksumIEEE([]byte(strings.Join(words, "\n") + "\n"))
+}
+
+// baseLetters returns word without accents for sorting, e.g. "é" as "e"
+func baseLetters(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFKD.String(word))
+}
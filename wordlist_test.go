@@ -0,0 +1,125 @@
+package mderive
+
+import (
+	"errors"
+	"github.io/decision2016/go-derived-mnemonic/wordlists"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateBuiltinWordlists(t *testing.T) {
+	checksums := map[Language]string{
+		English:  "c1dbd296",
+		Japanese: "acc1419",
+		Spanish:  "266e4f3d",
+		French:   "3e56b216",
+		Czech:    "d1b5fda0",
+	}
+
+	for _, lang := range Languages() {
+		wl, _ := lang.Wordlist()
+		checks := WordlistChecks{Checksum: checksums[lang], MaxPrefixLength: wl.PrefixLength()}
+		if err := ValidateWordlist(wl.Words(), checks); err != nil {
+			t.Fatalf("built-in %s wordlist is invalid: %s", lang, err)
+		}
+	}
+
+	wl, _ := English.Wordlist()
+	if wl.Checksum() != "c1dbd296" {
+		t.Fatalf("wrong checksum of english wordlist: %s", wl.Checksum())
+	}
+
+	if err := ValidateWordlist(wordlists.English, WordlistChecks{Sorted: true, MaxPrefixLength: 4}); err != nil {
+		t.Fatalf("english wordlist should be sorted and unique by 4 letters: %s", err)
+	}
+}
+
+func TestValidateWordlistErrors(t *testing.T) {
+	replace := func(idx int, word string) []string {
+		words := append([]string{}, wordlists.English...)
+		words[idx] = word
+		return words
+	}
+
+	tests := []struct {
+		name   string
+		words  []string
+		checks WordlistChecks
+		line   int
+	}{
+		{"short", wordlists.English[:2047], WordlistChecks{}, 0},
+		{"empty", replace(3, ""), WordlistChecks{}, 4},
+		{"whitespace", replace(5, "two words"), WordlistChecks{}, 6},
+		{"nfc", replace(7, "caf\u00e9"), WordlistChecks{}, 8},
+		{"duplicated", replace(1, "abandon"), WordlistChecks{}, 2},
+		{"unsorted", replace(0, "zzz"), WordlistChecks{Sorted: true}, 2},
+		{"prefix", replace(0, "abilitx"), WordlistChecks{MaxPrefixLength: 4}, 0},
+		{"checksum", wordlists.English, WordlistChecks{Checksum: "00000000"}, 0},
+	}
+
+	for _, test := range tests {
+		err := ValidateWordlist(test.words, test.checks)
+
+		var wordlistErr *WordlistError
+		if !errors.As(err, &wordlistErr) {
+			t.Fatalf("%s wordlist should be rejected, got %v", test.name, err)
+		}
+
+		if wordlistErr.Line != test.line {
+			t.Fatalf("%s wordlist rejected at line %d, expected %d", test.name, wordlistErr.Line, test.line)
+		}
+	}
+}
+
+func TestLoadWordlist(t *testing.T) {
+	words := make([]string, len(wordlists.English))
+	for idx, word := range wordlists.English {
+		words[idx] = strings.ToUpper(word)
+	}
+
+	path := filepath.Join(t.TempDir(), "upper.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(words, "\r\n")+"\r\n"), 0600); err != nil {
+		t.Fatalf("write wordlist failed: %s", err)
+	}
+
+	lang, err := LoadWordlist("Upper-Case", path, WordlistChecks{Sorted: true, MaxPrefixLength: 4})
+	if err != nil {
+		t.Fatalf("load wordlist failed: %s", err)
+	}
+	t.Cleanup(func() { unregisterLanguage(lang) })
+
+	if parsed, err := ParseLanguage("upper_case"); err != nil || parsed != lang || lang.String() != "upper_case" {
+		t.Fatalf("loaded wordlist should be registered by its name")
+	}
+
+	if _, err := LoadWordlist("upper_case", path, WordlistChecks{}); err != ErrLanguageExists {
+		t.Fatalf("wordlist with the same name should be rejected, got %v", err)
+	}
+
+	m, err := MnemonicFromEntropy(make([]byte, 16), lang)
+	if err != nil {
+		t.Fatalf("create mnemonic with custom wordlist failed: %s", err)
+	}
+
+	expect := strings.Repeat("ABANDON ", 11) + "ABOUT"
+	if m.Phrase() != expect {
+		t.Fatalf("wrong mnemonic of custom wordlist: %s", m.Phrase())
+	}
+
+	detected, err := DetectLanguage(strings.Fields(expect))
+	if err != nil || len(detected) != 1 || detected[0] != lang {
+		t.Fatalf("custom language should be detected, got %v", detected)
+	}
+
+	entropy, err := EntropyFromMnemonic(strings.Fields("ABAN ABAN ABAN ABAN ABAN ABAN ABAN ABAN ABAN ABAN ABAN ABOU"))
+	if err != nil || len(entropy) != 16 {
+		t.Fatalf("abbreviated mnemonic of custom wordlist should be decoded: %v", err)
+	}
+
+	unregisterLanguage(lang)
+	if _, err := ParseLanguage("upper_case"); err != ErrLanguageUnsupported {
+		t.Fatalf("unregistered wordlist should be removed, got %v", err)
+	}
+}